@@ -0,0 +1,297 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the locale used by DefaultCatalog when none of the requested ones is available.
+const DefaultLocale = "en"
+
+// CatalogEntry describes an error code: its default kind and HTTP status, and its message templates per locale.
+// Templates may reference the error metadata, attached with WithField, by its key: "brand {brand_id} not found".
+type CatalogEntry struct {
+	Code        CodeType          `json:"code"`
+	Kind        KindType          `json:"kind"`
+	HTTPStatus  int               `json:"http_status"`
+	Description string            `json:"description,omitempty"`
+	Messages    map[string]string `json:"messages"`
+}
+
+// Catalog is a registry of error codes, which centralizes their semantic and their messages in several locales.
+type Catalog struct {
+	mux           sync.RWMutex
+	defaultLocale string
+	entries       map[CodeType]CatalogEntry
+}
+
+// DefaultCatalog is the Catalog used by the package level functions, and by the http package to localize error responses.
+var DefaultCatalog = NewCatalog(DefaultLocale)
+
+// NewCatalog creates a new, empty, Catalog whose messages fall back to the given locale.
+func NewCatalog(defaultLocale string) *Catalog {
+	return &Catalog{defaultLocale: defaultLocale, entries: map[CodeType]CatalogEntry{}}
+}
+
+// Register adds the entry to the Catalog, overriding any other entry with the same code.
+// Kind defaults to KindUnexpected, and HTTPStatus to the one registered for its Kind.
+// It fails if the entry has no code, or no message in the default locale of the Catalog.
+func (c *Catalog) Register(entry CatalogEntry) error {
+	if entry.Code == "" {
+		return New("catalog entry must have a code").WithKind(KindInvalidInput)
+	}
+
+	if _, ok := entry.Messages[c.defaultLocale]; !ok {
+		return New("catalog entry %s must have a message in the default locale %s", entry.Code, c.defaultLocale).
+			WithKind(KindInvalidInput)
+	}
+
+	if entry.Kind == "" {
+		entry.Kind = KindUnexpected
+	}
+
+	if entry.HTTPStatus == 0 {
+		entry.HTTPStatus = HTTPStatusOfKind(entry.Kind)
+	}
+
+	messages := make(map[string]string, len(entry.Messages))
+	for locale, msg := range entry.Messages {
+		messages[locale] = msg
+	}
+	entry.Messages = messages
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.entries[entry.Code] = entry
+	return nil
+}
+
+// MustRegister works like Register, but panics if the entry is invalid.
+func (c *Catalog) MustRegister(entry CatalogEntry) {
+	if err := c.Register(entry); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the entry registered for the given code.
+func (c *Catalog) Lookup(code CodeType) (CatalogEntry, bool) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	entry, ok := c.entries[code]
+	return entry, ok
+}
+
+// Entries returns every registered entry, sorted by code.
+func (c *Catalog) Entries() []CatalogEntry {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	entries := make([]CatalogEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Code < entries[j].Code
+	})
+
+	return entries
+}
+
+// New returns a new instance of CustomError with the kind of the given code, the given metadata attached and
+// its message rendered in the default locale. Unregistered codes produce a CustomError whose message is the code itself.
+func (c *Catalog) New(code CodeType, fields map[string]interface{}) CustomError {
+	entry, ok := c.Lookup(code)
+	if !ok {
+		return New("%s", code).WithCode(code).WithFields(fields)
+	}
+
+	return New("%s", render(entry.Messages[c.defaultLocale], fields)).
+		WithKind(entry.Kind).
+		WithCode(code).
+		WithFields(fields)
+}
+
+// Message renders the message registered for the code of err in the first of the given locales that is available,
+// falling back to the default locale. Each locale is matched by its language when there's no exact match, so "pt"
+// matches "pt-BR" and vice versa, before the next locale is tried. It returns false if the code of err isn't registered.
+func (c *Catalog) Message(err error, locales ...string) (string, bool) {
+	entry, ok := c.Lookup(Code(err))
+	if !ok {
+		return "", false
+	}
+
+	return render(entry.Messages[c.matchLocale(entry, locales)], Fields(err)), true
+}
+
+func (c *Catalog) matchLocale(entry CatalogEntry, locales []string) string {
+	available := sortedLocales(entry.Messages)
+	for _, locale := range locales {
+		locale = normalizeLocale(locale)
+		for _, candidate := range available {
+			if normalizeLocale(candidate) == locale {
+				return candidate
+			}
+		}
+
+		language := localeLanguage(locale)
+		for _, candidate := range available {
+			if localeLanguage(normalizeLocale(candidate)) == language {
+				return candidate
+			}
+		}
+	}
+
+	return c.defaultLocale
+}
+
+// ExportJSON writes every registered entry to w as a JSON array, sorted by code.
+func (c *Catalog) ExportJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+
+	return enc.Encode(c.Entries())
+}
+
+// ExportMarkdown writes every registered entry to w as a Markdown table, sorted by code, suited for API docs.
+func (c *Catalog) ExportMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| Code | Kind | HTTP Status | Description | Messages |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, entry := range c.Entries() {
+		locales := sortedLocales(entry.Messages)
+
+		messages := make([]string, len(locales))
+		for i, locale := range locales {
+			messages[i] = fmt.Sprintf("**%s**: %s", locale, escapeMarkdown(entry.Messages[locale]))
+		}
+
+		fmt.Fprintf(&b, "| `%s` | `%s` | %d | %s | %s |\n",
+			entry.Code, entry.Kind, entry.HTTPStatus, escapeMarkdown(entry.Description), strings.Join(messages, "<br>"))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ExportFile writes every registered entry to the file at path, creating or truncating it.
+// The format is picked by the file extension: ".json" for ExportJSON, ".md" for ExportMarkdown.
+func (c *Catalog) ExportFile(path string) (err error) {
+	var export func(io.Writer) error
+	switch filepath.Ext(path) {
+	case ".json":
+		export = c.ExportJSON
+	case ".md":
+		export = c.ExportMarkdown
+	default:
+		return New("unsupported catalog export format %q, use .json or .md", filepath.Ext(path)).
+			WithKind(KindInvalidInput)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	return export(f)
+}
+
+// ExportCatalog writes every entry of DefaultCatalog to the file at path. Check Catalog.ExportFile for details.
+// It's meant to be called by a small program that imports the packages registering the codes, so the docs can be
+// generated with a go:generate directive, e.g. "//go:generate go run ./cmd/errdocs docs/errors.md".
+func ExportCatalog(path string) error {
+	return DefaultCatalog.ExportFile(path)
+}
+
+// RegisterCode adds the entry to DefaultCatalog. Check Catalog.Register for details.
+func RegisterCode(entry CatalogEntry) error {
+	return DefaultCatalog.Register(entry)
+}
+
+// MustRegisterCode adds the entry to DefaultCatalog. It panics if the entry is invalid.
+func MustRegisterCode(entry CatalogEntry) {
+	DefaultCatalog.MustRegister(entry)
+}
+
+// FromCode returns a new instance of CustomError described by the given code of DefaultCatalog.
+// Check Catalog.New for details.
+func FromCode(code CodeType, fields map[string]interface{}) CustomError {
+	return DefaultCatalog.New(code, fields)
+}
+
+// LocalizedMessage renders the message of err, registered in DefaultCatalog, in the first available of the given locales.
+// Check Catalog.Message for details.
+func LocalizedMessage(err error, locales ...string) (string, bool) {
+	return DefaultCatalog.Message(err, locales...)
+}
+
+// render replaces every {key} of the template by the value of the matching field.
+// Placeholders without a matching field are kept as is.
+func render(template string, fields map[string]interface{}) string {
+	if len(fields) == 0 {
+		return template
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		b.WriteString(template[:start])
+		if value, ok := fields[template[start+1:end]]; ok {
+			fmt.Fprint(&b, value)
+		} else {
+			b.WriteString(template[start : end+1])
+		}
+
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+
+	return b.String()
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+func localeLanguage(locale string) string {
+	return strings.SplitN(locale, "-", 2)[0]
+}
+
+func sortedLocales(messages map[string]string) []string {
+	locales := make([]string, 0, len(messages))
+	for locale := range messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	return locales
+}
+
+func escapeMarkdown(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
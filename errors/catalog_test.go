@@ -0,0 +1,169 @@
+package errors_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+func newTestCatalog() *errors.Catalog {
+	catalog := errors.NewCatalog("en")
+	catalog.MustRegister(errors.CatalogEntry{
+		Code:        "BRAND_NOT_FOUND",
+		Kind:        errors.KindNotFound,
+		Description: "The brand doesn't exist",
+		Messages: map[string]string{
+			"en":    "brand {brand_id} not found",
+			"pt-BR": "marca {brand_id} não encontrada",
+		},
+	})
+
+	return catalog
+}
+
+func TestCatalog(t *testing.T) {
+	t.Run("should reject entries without a message in the default locale", func(t *testing.T) {
+		err := errors.NewCatalog("en").Register(errors.CatalogEntry{
+			Code:     "MOCKED_CODE",
+			Messages: map[string]string{"pt-BR": "mensagem"},
+		})
+
+		if errors.Kind(err) != errors.KindInvalidInput {
+			t.Errorf("expected '%s', got '%s'", errors.KindInvalidInput, errors.Kind(err))
+		}
+	})
+
+	t.Run("should default the HTTP status to the one of the kind", func(t *testing.T) {
+		entry, ok := newTestCatalog().Lookup("BRAND_NOT_FOUND")
+
+		if !ok || entry.HTTPStatus != 404 {
+			t.Errorf("expected HTTP status 404, got %d", entry.HTTPStatus)
+		}
+	})
+
+	t.Run("should create errors described by the code", func(t *testing.T) {
+		err := newTestCatalog().New("BRAND_NOT_FOUND", map[string]interface{}{"brand_id": "dito"})
+
+		if err.Error() != "brand dito not found" {
+			t.Errorf("expected 'brand dito not found', got '%s'", err.Error())
+		}
+
+		if errors.Kind(err) != errors.KindNotFound {
+			t.Errorf("expected '%s', got '%s'", errors.KindNotFound, errors.Kind(err))
+		}
+
+		if errors.Code(err) != "BRAND_NOT_FOUND" {
+			t.Errorf("expected 'BRAND_NOT_FOUND', got '%s'", errors.Code(err))
+		}
+	})
+
+	t.Run("should render the message in the first available locale", func(t *testing.T) {
+		catalog := newTestCatalog()
+		err := catalog.New("BRAND_NOT_FOUND", map[string]interface{}{"brand_id": "dito"})
+
+		tt := []struct {
+			locales  []string
+			expected string
+		}{
+			{locales: []string{"pt-BR"}, expected: "marca dito não encontrada"},
+			{locales: []string{"pt_br"}, expected: "marca dito não encontrada"},
+			{locales: []string{"fr", "pt"}, expected: "marca dito não encontrada"},
+			{locales: []string{"en-US"}, expected: "brand dito not found"},
+			{locales: []string{"fr"}, expected: "brand dito not found"},
+			{expected: "brand dito not found"},
+		}
+
+		for _, tc := range tt {
+			if msg, ok := catalog.Message(err, tc.locales...); !ok || msg != tc.expected {
+				t.Errorf("locales %v: expected '%s', got '%s'", tc.locales, tc.expected, msg)
+			}
+		}
+	})
+
+	t.Run("should not render messages of unregistered codes", func(t *testing.T) {
+		if _, ok := newTestCatalog().Message(errors.New("mocked message").WithCode("MOCKED_CODE")); ok {
+			t.Errorf("expected no message")
+		}
+	})
+
+	t.Run("should export the catalog as Markdown", func(t *testing.T) {
+		var b bytes.Buffer
+		if err := newTestCatalog().ExportMarkdown(&b); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		expected := "| Code | Kind | HTTP Status | Description | Messages |\n" +
+			"| --- | --- | --- | --- | --- |\n" +
+			"| `BRAND_NOT_FOUND` | `NOT_FOUND` | 404 | The brand doesn't exist | **en**: brand {brand_id} not found<br>**pt-BR**: marca {brand_id} não encontrada |\n"
+		if b.String() != expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", expected, b.String())
+		}
+	})
+
+	t.Run("should export the catalog as JSON", func(t *testing.T) {
+		var b bytes.Buffer
+		if err := newTestCatalog().ExportJSON(&b); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		expected := `[
+  {
+    "code": "BRAND_NOT_FOUND",
+    "kind": "NOT_FOUND",
+    "http_status": 404,
+    "description": "The brand doesn't exist",
+    "messages": {
+      "en": "brand {brand_id} not found",
+      "pt-BR": "marca {brand_id} não encontrada"
+    }
+  }
+]
+`
+		if b.String() != expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", expected, b.String())
+		}
+	})
+
+	t.Run("should export the catalog to a file in the format of its extension", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "catalog")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		defer os.RemoveAll(dir)
+
+		catalog := newTestCatalog()
+		for _, name := range []string{"errors.md", "errors.json"} {
+			path := filepath.Join(dir, name)
+			if err := catalog.ExportFile(path); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var expected bytes.Buffer
+			if filepath.Ext(name) == ".md" {
+				catalog.ExportMarkdown(&expected)
+			} else {
+				catalog.ExportJSON(&expected)
+			}
+
+			got, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if string(got) != expected.String() {
+				t.Errorf("expected:\n%s\ngot:\n%s", expected.String(), got)
+			}
+		}
+	})
+
+	t.Run("should not export the catalog to files of unsupported formats", func(t *testing.T) {
+		err := newTestCatalog().ExportFile("errors.txt")
+		if errors.Kind(err) != errors.KindInvalidInput {
+			t.Errorf("expected an invalid input error, got %v", err)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package errors
 import (
 	e "errors"
 	"fmt"
+	"strings"
 )
 
 // CodeType is a string that contains error's code description
@@ -20,6 +21,9 @@ type CustomError struct {
 	kind    KindType
 	code    CodeType
 	message string
+	cause   error
+	stack   *stack
+	fields  *field
 }
 
 const (
@@ -39,6 +43,14 @@ const (
 	KindUnauthenticated KindType = "UNAUTHENTICATED"
 	// KindUnauthorized are errors caused by an unauthorized call
 	KindUnauthorized KindType = "UNAUTHORIZED"
+	// KindRateLimited are errors caused by calls that exceeded the allowed rate or quota
+	KindRateLimited KindType = "RATE_LIMITED"
+	// KindPreconditionFailed are errors caused by calls whose preconditions don't hold on the current state of the system
+	KindPreconditionFailed KindType = "PRECONDITION_FAILED"
+	// KindUnavailable are errors caused by a dependency, or the application itself, being temporarily unavailable
+	KindUnavailable KindType = "UNAVAILABLE"
+	// KindTimeout are errors caused by operations that didn't finish within their deadline
+	KindTimeout KindType = "TIMEOUT"
 )
 
 // New returns a new instance of CustomError with the given message
@@ -50,6 +62,30 @@ func New(message string, args ...interface{}) CustomError {
 	}
 }
 
+// Wrap returns a new instance of CustomError caused by err, which stays reachable through Unwrap,
+// and thus through the standard library errors.Is and errors.As functions.
+// Kind and Code are inherited from err when it's a CustomError.
+// Its message is prefixed to the message of err, unless it's empty, in which case the message of err is kept as is.
+// If err is nil, it behaves like New, so, unlike pkg/errors Wrap, the result is never nil, since it's a CustomError:
+// check err before wrapping it, e.g. if err != nil { return errors.Wrap(err, "message") }.
+func Wrap(err error, message string) CustomError {
+	return Wrapf(err, "%s", message)
+}
+
+// Wrapf works like Wrap, but formats the message according to the given format specifier.
+func Wrapf(err error, format string, args ...interface{}) CustomError {
+	ce := New(format, args...)
+	ce.cause = err
+
+	if err != nil {
+		cause := customErrorOf(err)
+		ce.kind = cause.kind
+		ce.code = cause.code
+	}
+
+	return ce
+}
+
 // WithKind return a copy of the CustomError with the given KindType filled
 func (ce CustomError) WithKind(kind KindType) CustomError {
 	ce.kind = kind
@@ -62,9 +98,70 @@ func (ce CustomError) WithCode(code CodeType) CustomError {
 	return ce
 }
 
-// Error returns CustomError message
+// WithField return a copy of the CustomError with the given key/value metadata attached.
+// Metadata should be used for context like entity IDs, instead of interpolating it into the message,
+// so errors with the same cause keep the same message.
+func (ce CustomError) WithField(key string, value interface{}) CustomError {
+	ce.fields = &field{key: key, value: value, next: ce.fields}
+	return ce
+}
+
+// WithFields return a copy of the CustomError with all the given key/value metadata attached.
+func (ce CustomError) WithFields(fields map[string]interface{}) CustomError {
+	for key, value := range fields {
+		ce = ce.WithField(key, value)
+	}
+	return ce
+}
+
+// WithStack return a copy of the CustomError with the stack trace of the goroutine that calls it.
+// It's supposed to be called where the error is created, e.g. errors.New("message").WithStack().
+func (ce CustomError) WithStack() CustomError {
+	ce.stack = callers(3)
+	return ce
+}
+
+// Error returns CustomError message, followed by the message of its cause, if any
 func (ce CustomError) Error() string {
-	return ce.message
+	if ce.cause == nil {
+		return ce.message
+	}
+
+	if ce.message == "" {
+		return ce.cause.Error()
+	}
+
+	return ce.message + ": " + ce.cause.Error()
+}
+
+// Unwrap returns the cause of the CustomError, if any
+func (ce CustomError) Unwrap() error {
+	return ce.cause
+}
+
+// Format implements fmt.Formatter. The verbs %s and %v print the error message, while %+v prints
+// the whole cause chain, one error per line, followed by the stack traces captured along it.
+func (ce CustomError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		var b strings.Builder
+		b.WriteString(ce.message)
+		if ce.stack != nil {
+			ce.stack.format(&b)
+		}
+		if ce.cause != nil && b.Len() == 0 {
+			fmt.Fprintf(&b, "%+v", ce.cause)
+		} else if ce.cause != nil {
+			fmt.Fprintf(&b, "\ncaused by: %+v", ce.cause)
+		}
+
+		// An empty message, like the one of Wrap(err, ""), doesn't take a line of its own.
+		fmt.Fprint(s, strings.TrimPrefix(b.String(), "\n"))
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", ce.Error())
+	default:
+		fmt.Fprint(s, ce.Error())
+	}
 }
 
 // NewMissingRequiredDependency creates a new error that indicates a missing required dependency.
@@ -74,21 +171,65 @@ func NewMissingRequiredDependency(name string) error {
 }
 
 // Kind this method receives an error, then compares its interface type with the CustomError interface
-// if the interfaces types matches, returns its kind
+// if the interfaces types matches, returns its kind. For MultiErrors, returns the kind of its most relevant error.
 func Kind(err error) KindType {
-	var customError CustomError
-	if e.As(err, &customError) {
-		return customError.kind
-	}
-	return KindUnexpected
+	return customErrorOf(err).kind
 }
 
 // Kind this method receives an error, then compares its interface type with the CustomError interface
-// if the interfaces types matches, returns its Code
+// if the interfaces types matches, returns its Code. For MultiErrors, returns the code of its most relevant error.
 func Code(err error) CodeType {
+	return customErrorOf(err).code
+}
+
+// customErrorOf finds the first CustomError, or MultiError, of the err chain.
+// It falls back to a CustomError with the default Kind and Code when there's none.
+func customErrorOf(err error) CustomError {
+	for cur := err; cur != nil; cur = e.Unwrap(cur) {
+		switch v := cur.(type) {
+		case CustomError:
+			return v
+		case MultiError:
+			return v.primary()
+		}
+	}
+
 	var customError CustomError
-	if e.As(err, &customError) {
-		return customError.code
+	if err != nil && e.As(err, &customError) {
+		return customError
+	}
+
+	return CustomError{kind: KindUnexpected, code: CodeUnknown}
+}
+
+// Fields receives an error and returns the metadata attached to every CustomError of its chain.
+// When the same key is attached more than once, the outermost, or latest, value wins.
+// It returns nil if there's no metadata.
+func Fields(err error) map[string]interface{} {
+	var fields map[string]interface{}
+	for ; err != nil; err = e.Unwrap(err) {
+		ce, ok := err.(CustomError)
+		if !ok {
+			continue
+		}
+
+		for f := ce.fields; f != nil; f = f.next {
+			if fields == nil {
+				fields = map[string]interface{}{}
+			}
+
+			if _, ok := fields[f.key]; !ok {
+				fields[f.key] = f.value
+			}
+		}
 	}
-	return CodeUnknown
+
+	return fields
+}
+
+// field is an immutable linked list of metadata, so CustomError copies can share it and stay comparable.
+type field struct {
+	key   string
+	value interface{}
+	next  *field
 }
@@ -2,6 +2,9 @@ package errors_test
 
 import (
 	e "errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/ditointernet/go-dito/errors"
@@ -131,3 +134,110 @@ func TestCode(t *testing.T) {
 		})
 	}
 }
+
+func TestWrap(t *testing.T) {
+	t.Run("should keep the cause reachable through the standard library", func(t *testing.T) {
+		cause := e.New("cause")
+		err := errors.Wrap(cause, "mocked message")
+
+		if !e.Is(err, cause) {
+			t.Errorf("expected error to be '%s'", cause)
+		}
+
+		if err.Error() != "mocked message: cause" {
+			t.Errorf("expected 'mocked message: cause', got '%s'", err.Error())
+		}
+	})
+
+	t.Run("should keep the message of the cause when no message is given", func(t *testing.T) {
+		if err := errors.Wrap(e.New("cause"), ""); err.Error() != "cause" {
+			t.Errorf("expected 'cause', got '%s'", err.Error())
+		}
+	})
+
+	t.Run("should inherit the Kind and Code of a CustomError cause", func(t *testing.T) {
+		cause := errors.New("cause").WithKind(errors.KindNotFound).WithCode("MOCKED_CODE")
+		err := errors.Wrapf(cause, "mocked message %d", 1000)
+
+		if errors.Kind(err) != errors.KindNotFound {
+			t.Errorf("expected '%s', got '%s'", errors.KindNotFound, errors.Kind(err))
+		}
+
+		if errors.Code(err) != "MOCKED_CODE" {
+			t.Errorf("expected 'MOCKED_CODE', got '%s'", errors.Code(err))
+		}
+
+		var target errors.CustomError
+		if !e.As(err.Unwrap(), &target) || target.Error() != "cause" {
+			t.Errorf("expected cause to be a CustomError")
+		}
+	})
+}
+
+func TestStackTrace(t *testing.T) {
+	t.Run("should return an empty stack trace when it wasn't captured", func(t *testing.T) {
+		if stack := errors.StackTrace(errors.New("mocked message")); stack != "" {
+			t.Errorf("expected no stack trace, got '%s'", stack)
+		}
+	})
+
+	t.Run("should return the stack trace of the innermost error that captured it", func(t *testing.T) {
+		err := errors.Wrap(errors.New("cause").WithStack(), "mocked message")
+
+		stack := errors.StackTrace(err)
+		if !strings.HasPrefix(stack, "goroutine 1 [running]:\ngithub.com/ditointernet/go-dito/errors_test.TestStackTrace.func2(...)\n") {
+			t.Errorf("unexpected stack trace: %s", stack)
+		}
+	})
+
+	t.Run("should print the whole cause chain with the %+v verb", func(t *testing.T) {
+		err := errors.Wrap(errors.New("cause").WithStack(), "mocked message")
+
+		out := fmt.Sprintf("%+v", err)
+		if !strings.HasPrefix(out, "mocked message\ncaused by: cause\ngithub.com/ditointernet/go-dito/errors_test.TestStackTrace.func3(...)\n") {
+			t.Errorf("unexpected output: %s", out)
+		}
+
+		if out := fmt.Sprintf("%v", err); out != "mocked message: cause" {
+			t.Errorf("expected 'mocked message: cause', got '%s'", out)
+		}
+	})
+
+	t.Run("should not print an empty line for wrappers without message", func(t *testing.T) {
+		out := fmt.Sprintf("%+v", errors.Wrap(errors.New("cause").WithStack(), ""))
+		if !strings.HasPrefix(out, "cause\ngithub.com/ditointernet/go-dito/errors_test.TestStackTrace.func4(...)\n") {
+			t.Errorf("unexpected output: %s", out)
+		}
+
+		out = fmt.Sprintf("%+v", errors.Wrap(errors.New("cause").WithStack(), "").WithStack())
+		if !strings.HasPrefix(out, "github.com/ditointernet/go-dito/errors_test.TestStackTrace.func4(...)\n") {
+			t.Errorf("unexpected output: %s", out)
+		}
+	})
+}
+
+func TestFields(t *testing.T) {
+	t.Run("should return nil when there's no metadata", func(t *testing.T) {
+		if fields := errors.Fields(e.New("new error")); fields != nil {
+			t.Errorf("expected no fields, got %v", fields)
+		}
+	})
+
+	t.Run("should return the metadata of the whole chain, favoring the outermost values", func(t *testing.T) {
+		cause := errors.New("cause").WithField("brand_id", "dito").WithField("status", 404)
+		err := errors.Wrap(cause, "mocked message").WithFields(map[string]interface{}{"status": 502, "retries": 3})
+
+		expected := map[string]interface{}{"brand_id": "dito", "status": 502, "retries": 3}
+		if fields := errors.Fields(err); !reflect.DeepEqual(fields, expected) {
+			t.Errorf("expected %v, got %v", expected, fields)
+		}
+	})
+
+	t.Run("should keep CustomError comparable", func(t *testing.T) {
+		err := errors.New("mocked message").WithField("brand_id", "dito")
+
+		if !e.Is(err, err) {
+			t.Errorf("expected error to be itself")
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package errors
+
+import "sync"
+
+// KindInfo describes how a KindType is translated into other protocols.
+type KindInfo struct {
+	// HTTPStatus is the HTTP status code that represents the kind.
+	HTTPStatus int
+	// GRPCCode is the numeric value of the gRPC status code (google.golang.org/grpc/codes.Code) that represents the kind.
+	GRPCCode uint32
+	// Retryable indicates whether the failed operation may succeed if retried later.
+	Retryable bool
+}
+
+// gRPC status codes used by the built-in kinds. They mirror google.golang.org/grpc/codes,
+// which is not imported to keep this package dependency free.
+const (
+	grpcCodeUnknown            uint32 = 2
+	grpcCodeInvalidArgument    uint32 = 3
+	grpcCodeDeadlineExceeded   uint32 = 4
+	grpcCodeNotFound           uint32 = 5
+	grpcCodeAlreadyExists      uint32 = 6
+	grpcCodePermissionDenied   uint32 = 7
+	grpcCodeResourceExhausted  uint32 = 8
+	grpcCodeFailedPrecondition uint32 = 9
+	grpcCodeInternal           uint32 = 13
+	grpcCodeUnavailable        uint32 = 14
+	grpcCodeUnauthenticated    uint32 = 16
+)
+
+type kindRegistry struct {
+	mux   sync.RWMutex
+	kinds map[KindType]KindInfo
+	// order keeps the registration order, so reverse lookups are deterministic.
+	order []KindType
+}
+
+var registry = newKindRegistry()
+
+func newKindRegistry() *kindRegistry {
+	r := &kindRegistry{kinds: map[KindType]KindInfo{}}
+
+	r.register(KindInvalidInput, KindInfo{HTTPStatus: 400, GRPCCode: grpcCodeInvalidArgument})
+	r.register(KindUnauthenticated, KindInfo{HTTPStatus: 401, GRPCCode: grpcCodeUnauthenticated})
+	r.register(KindUnauthorized, KindInfo{HTTPStatus: 403, GRPCCode: grpcCodePermissionDenied})
+	r.register(KindNotFound, KindInfo{HTTPStatus: 404, GRPCCode: grpcCodeNotFound})
+	r.register(KindConflict, KindInfo{HTTPStatus: 409, GRPCCode: grpcCodeAlreadyExists})
+	r.register(KindPreconditionFailed, KindInfo{HTTPStatus: 412, GRPCCode: grpcCodeFailedPrecondition})
+	r.register(KindRateLimited, KindInfo{HTTPStatus: 429, GRPCCode: grpcCodeResourceExhausted, Retryable: true})
+	r.register(KindUnexpected, KindInfo{HTTPStatus: 500, GRPCCode: grpcCodeUnknown})
+	r.register(KindInternal, KindInfo{HTTPStatus: 500, GRPCCode: grpcCodeInternal})
+	r.register(KindUnavailable, KindInfo{HTTPStatus: 503, GRPCCode: grpcCodeUnavailable, Retryable: true})
+	r.register(KindTimeout, KindInfo{HTTPStatus: 504, GRPCCode: grpcCodeDeadlineExceeded, Retryable: true})
+
+	return r
+}
+
+func (r *kindRegistry) register(kind KindType, info KindInfo) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if _, ok := r.kinds[kind]; !ok {
+		r.order = append(r.order, kind)
+	}
+	r.kinds[kind] = info
+}
+
+// RegisterKind registers a custom KindType, or overrides a built-in one, with the information used to translate it
+// into other protocols. It's supposed to be called during the application initialization.
+func RegisterKind(kind KindType, info KindInfo) {
+	registry.register(kind, info)
+}
+
+// LookupKind returns the information registered for the given KindType.
+func LookupKind(kind KindType) (KindInfo, bool) {
+	registry.mux.RLock()
+	defer registry.mux.RUnlock()
+
+	info, ok := registry.kinds[kind]
+	return info, ok
+}
+
+// InfoOf returns the information registered for the Kind of the given error.
+// Unregistered kinds fall back to the information of KindUnexpected.
+func InfoOf(err error) KindInfo {
+	return infoOfKind(Kind(err))
+}
+
+func infoOfKind(kind KindType) KindInfo {
+	if info, ok := LookupKind(kind); ok {
+		return info
+	}
+
+	info, _ := LookupKind(KindUnexpected)
+	return info
+}
+
+// HTTPStatus returns the HTTP status code registered for the Kind of the given error.
+func HTTPStatus(err error) int {
+	return InfoOf(err).HTTPStatus
+}
+
+// HTTPStatusOfKind returns the HTTP status code registered for the given KindType.
+func HTTPStatusOfKind(kind KindType) int {
+	return infoOfKind(kind).HTTPStatus
+}
+
+// GRPCCodeOfKind returns the numeric value of the gRPC status code registered for the given KindType.
+func GRPCCodeOfKind(kind KindType) uint32 {
+	return infoOfKind(kind).GRPCCode
+}
+
+// IsRetryable indicates whether the Kind of the given error is registered as retryable.
+func IsRetryable(err error) bool {
+	return InfoOf(err).Retryable
+}
+
+// KindFromHTTPStatus returns the first registered KindType whose HTTP status code is the given one.
+// Unregistered client error statuses fall back to KindInvalidInput, and any other status to KindUnexpected.
+func KindFromHTTPStatus(status int) KindType {
+	registry.mux.RLock()
+	defer registry.mux.RUnlock()
+
+	for _, kind := range registry.order {
+		if registry.kinds[kind].HTTPStatus == status {
+			return kind
+		}
+	}
+
+	if status >= 400 && status < 500 {
+		return KindInvalidInput
+	}
+
+	return KindUnexpected
+}
+
+// KindFromGRPCCode returns the first registered KindType whose gRPC status code is the given one.
+// Unregistered codes fall back to KindUnexpected.
+func KindFromGRPCCode(code uint32) KindType {
+	registry.mux.RLock()
+	defer registry.mux.RUnlock()
+
+	for _, kind := range registry.order {
+		if registry.kinds[kind].GRPCCode == code {
+			return kind
+		}
+	}
+
+	return KindUnexpected
+}
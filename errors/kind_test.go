@@ -0,0 +1,93 @@
+package errors_test
+
+import (
+	e "errors"
+	"testing"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+func TestRegisterKind(t *testing.T) {
+	t.Run("should translate custom kinds into other protocols", func(t *testing.T) {
+		var kindPaymentRequired errors.KindType = "PAYMENT_REQUIRED"
+		errors.RegisterKind(kindPaymentRequired, errors.KindInfo{HTTPStatus: 402, GRPCCode: 9, Retryable: true})
+
+		err := errors.New("mocked message").WithKind(kindPaymentRequired)
+
+		if status := errors.HTTPStatus(err); status != 402 {
+			t.Errorf("expected 402, got %d", status)
+		}
+
+		if info := errors.InfoOf(err); info.GRPCCode != 9 {
+			t.Errorf("expected gRPC code 9, got %d", info.GRPCCode)
+		}
+
+		if !errors.IsRetryable(err) {
+			t.Errorf("expected error to be retryable")
+		}
+
+		if kind := errors.KindFromHTTPStatus(402); kind != kindPaymentRequired {
+			t.Errorf("expected '%s', got '%s'", kindPaymentRequired, kind)
+		}
+	})
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tt := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "go native error", err: e.New("new error"), expectedStatus: 500},
+		{name: "invalid input error", err: errors.New("message").WithKind(errors.KindInvalidInput), expectedStatus: 400},
+		{name: "rate limited error", err: errors.New("message").WithKind(errors.KindRateLimited), expectedStatus: 429},
+		{name: "precondition failed error", err: errors.New("message").WithKind(errors.KindPreconditionFailed), expectedStatus: 412},
+		{name: "unavailable error", err: errors.New("message").WithKind(errors.KindUnavailable), expectedStatus: 503},
+		{name: "timeout error", err: errors.New("message").WithKind(errors.KindTimeout), expectedStatus: 504},
+		{name: "unregistered kind error", err: errors.New("message").WithKind("UNREGISTERED"), expectedStatus: 500},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if status := errors.HTTPStatus(tc.err); status != tc.expectedStatus {
+				t.Errorf("expected %d, got %d", tc.expectedStatus, status)
+			}
+		})
+	}
+}
+
+func TestKindFromHTTPStatus(t *testing.T) {
+	tt := []struct {
+		status       int
+		expectedKind errors.KindType
+	}{
+		{status: 404, expectedKind: errors.KindNotFound},
+		{status: 500, expectedKind: errors.KindUnexpected},
+		{status: 418, expectedKind: errors.KindInvalidInput},
+		{status: 502, expectedKind: errors.KindUnexpected},
+	}
+
+	for _, tc := range tt {
+		if kind := errors.KindFromHTTPStatus(tc.status); kind != tc.expectedKind {
+			t.Errorf("expected '%s' for status %d, got '%s'", tc.expectedKind, tc.status, kind)
+		}
+	}
+}
+
+func TestKindFromGRPCCode(t *testing.T) {
+	tt := []struct {
+		code         uint32
+		expectedKind errors.KindType
+	}{
+		{code: 5, expectedKind: errors.KindNotFound},
+		{code: 2, expectedKind: errors.KindUnexpected},
+		{code: 13, expectedKind: errors.KindInternal},
+		{code: 12, expectedKind: errors.KindUnexpected},
+	}
+
+	for _, tc := range tt {
+		if kind := errors.KindFromGRPCCode(tc.code); kind != tc.expectedKind {
+			t.Errorf("expected '%s' for code %d, got '%s'", tc.expectedKind, tc.code, kind)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package errors
+
+import (
+	e "errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultKindPrecedence is the order, from the most to the least relevant, used to pick the aggregate Kind of a MultiError.
+// Server side failures come first, since they are the ones that most likely need attention.
+// Kinds that aren't listed are less relevant than every listed one.
+var DefaultKindPrecedence = []KindType{
+	KindInternal,
+	KindUnexpected,
+	KindUnavailable,
+	KindTimeout,
+	KindUnauthenticated,
+	KindUnauthorized,
+	KindRateLimited,
+	KindPreconditionFailed,
+	KindConflict,
+	KindNotFound,
+	KindInvalidInput,
+}
+
+// MultiError is an error that aggregates several errors, like the ones of a batch operation.
+// Its Kind and Code are the ones of its most relevant error, according to a Kind precedence.
+// Every error stays reachable through the standard library errors.Is and errors.As functions.
+//
+// Its state is kept behind a pointer, so MultiError stays comparable, as errors.Is requires,
+// even when it is wrapped into a CustomError.
+type MultiError struct {
+	state *multiState
+}
+
+type multiState struct {
+	errs       []error
+	precedence []KindType
+}
+
+// NewMultiError returns a new instance of MultiError with the given errors. Nil errors are discarded.
+func NewMultiError(errs ...error) MultiError {
+	return MultiError{}.Append(errs...)
+}
+
+// Append return a copy of the MultiError with the given errors appended. Nil errors are discarded.
+func (me MultiError) Append(errs ...error) MultiError {
+	merged := make([]error, len(me.errs()), len(me.errs())+len(errs))
+	copy(merged, me.errs())
+
+	for _, err := range errs {
+		if err != nil {
+			merged = append(merged, err)
+		}
+	}
+
+	return MultiError{state: &multiState{errs: merged, precedence: me.kindPrecedence()}}
+}
+
+// WithKindPrecedence return a copy of the MultiError which picks its Kind according to the given precedence,
+// instead of DefaultKindPrecedence.
+func (me MultiError) WithKindPrecedence(kinds ...KindType) MultiError {
+	return MultiError{state: &multiState{errs: me.errs(), precedence: kinds}}
+}
+
+// Errors returns the aggregated errors.
+func (me MultiError) Errors() []error {
+	return me.errs()
+}
+
+// Len returns the number of aggregated errors.
+func (me MultiError) Len() int {
+	return len(me.errs())
+}
+
+// ErrorOrNil returns the MultiError if it aggregates any error, or nil otherwise.
+func (me MultiError) ErrorOrNil() error {
+	if len(me.errs()) == 0 {
+		return nil
+	}
+
+	return me
+}
+
+// Error returns the message of the aggregated errors
+func (me MultiError) Error() string {
+	errs := me.errs()
+	if len(errs) == 1 {
+		return errs[0].Error()
+	}
+
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d errors occurred: %s", len(errs), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the aggregated errors, following the Go 1.20 multiple errors contract.
+func (me MultiError) Unwrap() []error {
+	return me.errs()
+}
+
+// Is reports whether any of the aggregated errors matches target.
+// It makes errors.Is work with MultiError in Go versions older than 1.20.
+func (me MultiError) Is(target error) bool {
+	for _, err := range me.errs() {
+		if e.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first aggregated error that matches target.
+// It makes errors.As work with MultiError in Go versions older than 1.20.
+func (me MultiError) As(target interface{}) bool {
+	for _, err := range me.errs() {
+		if e.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// primary returns the most relevant of the aggregated errors.
+func (me MultiError) primary() CustomError {
+	precedence := me.kindPrecedence()
+	if precedence == nil {
+		precedence = DefaultKindPrecedence
+	}
+
+	rank := func(kind KindType) int {
+		for i, k := range precedence {
+			if k == kind {
+				return i
+			}
+		}
+		return len(precedence)
+	}
+
+	primary := New("").WithKind(KindUnexpected)
+	primaryRank := -1
+	for _, err := range me.errs() {
+		ce := customErrorOf(err)
+		if r := rank(ce.kind); primaryRank < 0 || r < primaryRank {
+			primary, primaryRank = ce, r
+		}
+	}
+
+	return primary
+}
+
+func (me MultiError) errs() []error {
+	if me.state == nil {
+		return nil
+	}
+
+	return me.state.errs
+}
+
+func (me MultiError) kindPrecedence() []KindType {
+	if me.state == nil {
+		return nil
+	}
+
+	return me.state.precedence
+}
@@ -0,0 +1,91 @@
+package errors_test
+
+import (
+	e "errors"
+	"testing"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+func TestMultiError(t *testing.T) {
+	notFound := errors.New("resource not found").WithKind(errors.KindNotFound).WithCode("RESOURCE_NOT_FOUND")
+	internal := errors.New("database is down").WithKind(errors.KindInternal).WithCode("DATABASE_DOWN")
+	native := e.New("native error")
+
+	t.Run("should discard nil errors", func(t *testing.T) {
+		err := errors.NewMultiError(nil, notFound).Append(nil)
+
+		if err.Len() != 1 {
+			t.Errorf("expected 1 error, got %d", err.Len())
+		}
+
+		if errors.NewMultiError(nil).ErrorOrNil() != nil {
+			t.Errorf("expected a nil error")
+		}
+	})
+
+	t.Run("should join the messages of every error", func(t *testing.T) {
+		if msg := errors.NewMultiError(notFound).Error(); msg != "resource not found" {
+			t.Errorf("expected 'resource not found', got '%s'", msg)
+		}
+
+		expected := "2 errors occurred: resource not found; native error"
+		if msg := errors.NewMultiError(notFound, native).Error(); msg != expected {
+			t.Errorf("expected '%s', got '%s'", expected, msg)
+		}
+	})
+
+	t.Run("should work with the standard library errors.Is and errors.As", func(t *testing.T) {
+		var err error = errors.NewMultiError(notFound, native)
+
+		if !e.Is(err, native) {
+			t.Errorf("expected error to be '%s'", native)
+		}
+
+		var target errors.CustomError
+		if !e.As(err, &target) || target.Error() != "resource not found" {
+			t.Errorf("expected to find the CustomError")
+		}
+	})
+
+	t.Run("should aggregate Kind and Code by the default precedence", func(t *testing.T) {
+		err := errors.NewMultiError(notFound, internal)
+
+		if errors.Kind(err) != errors.KindInternal {
+			t.Errorf("expected '%s', got '%s'", errors.KindInternal, errors.Kind(err))
+		}
+
+		if errors.Code(err) != "DATABASE_DOWN" {
+			t.Errorf("expected 'DATABASE_DOWN', got '%s'", errors.Code(err))
+		}
+	})
+
+	t.Run("should aggregate Kind by a custom precedence", func(t *testing.T) {
+		err := errors.NewMultiError(internal, notFound, native).WithKindPrecedence(errors.KindNotFound)
+
+		if errors.Kind(err) != errors.KindNotFound {
+			t.Errorf("expected '%s', got '%s'", errors.KindNotFound, errors.Kind(err))
+		}
+	})
+
+	t.Run("should favor the kind of the wrapping CustomError", func(t *testing.T) {
+		err := errors.Wrap(errors.NewMultiError(internal), "batch failed").WithKind(errors.KindConflict)
+
+		if errors.Kind(err) != errors.KindConflict {
+			t.Errorf("expected '%s', got '%s'", errors.KindConflict, errors.Kind(err))
+		}
+	})
+
+	t.Run("should be comparable when wrapped into a CustomError", func(t *testing.T) {
+		wrapped := errors.Wrap(errors.NewMultiError(internal, notFound), "batch failed")
+		other := errors.Wrap(errors.NewMultiError(internal, notFound), "batch failed")
+
+		if e.Is(wrapped, other) {
+			t.Error("expected errors wrapping distinct MultiErrors not to match")
+		}
+
+		if !e.Is(wrapped, wrapped) {
+			t.Error("expected the wrapped MultiError to match itself")
+		}
+	})
+}
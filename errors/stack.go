@@ -0,0 +1,59 @@
+package errors
+
+import (
+	e "errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+)
+
+const maxStackDepth = 32
+
+// stack is a pointer-friendly call stack, so CustomError stays comparable.
+type stack []uintptr
+
+func callers(skip int) *stack {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip, pcs)
+
+	s := stack(pcs[:n])
+	return &s
+}
+
+// format writes the stack frames in the same layout used by runtime/debug.Stack.
+func (s stack) format(w io.Writer) {
+	frames := runtime.CallersFrames(s)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(w, "\n%s(...)\n\t%s:%d", frame.Function, frame.File, frame.Line)
+		if frame.Func != nil {
+			fmt.Fprintf(w, " +0x%x", frame.PC-frame.Func.Entry())
+		}
+
+		if !more {
+			return
+		}
+	}
+}
+
+// StackTrace returns the stack trace captured by the innermost CustomError of the err chain that called WithStack,
+// formatted like a goroutine dump, as expected by Cloud Error Reporting. It returns an empty string if there's none.
+func StackTrace(err error) string {
+	var s *stack
+	for ; err != nil; err = e.Unwrap(err) {
+		if ce, ok := err.(CustomError); ok && ce.stack != nil {
+			s = ce.stack
+		}
+	}
+
+	if s == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("goroutine 1 [running]:")
+	s.format(&b)
+
+	return b.String()
+}
@@ -0,0 +1,12 @@
+package grpc
+
+import "context"
+
+// Logger is the contract of the logger used by the server interceptors, which is fulfilled by log.Logger.
+type Logger interface {
+	Debug(ctx context.Context, msg string, args ...interface{})
+	Info(ctx context.Context, msg string, args ...interface{})
+	Warning(ctx context.Context, msg string, args ...interface{})
+	Error(ctx context.Context, err error)
+	Critical(ctx context.Context, err error)
+}
@@ -0,0 +1,95 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+// ServerInterceptor structure responsible for translating the errors returned by gRPC handlers into gRPC statuses.
+type ServerInterceptor struct {
+	logger Logger
+}
+
+// NewServerInterceptor creates a new instance of the ServerInterceptor structure
+func NewServerInterceptor(logger Logger) (ServerInterceptor, error) {
+	if logger == nil {
+		return ServerInterceptor{}, errors.NewMissingRequiredDependency("logger")
+	}
+
+	return ServerInterceptor{logger: logger}, nil
+}
+
+// MustNewServerInterceptor creates a new instance of the ServerInterceptor structure.
+// It panics if any error is found.
+func MustNewServerInterceptor(logger Logger) ServerInterceptor {
+	interceptor, err := NewServerInterceptor(logger)
+	if err != nil {
+		panic(err)
+	}
+
+	return interceptor
+}
+
+// Unary is the grpc.UnaryServerInterceptor that logs the errors returned by unary handlers,
+// and converts them into gRPC statuses with ToStatus.
+func (i ServerInterceptor) Unary(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	res, err := handler(ctx, req)
+	if err != nil {
+		return res, i.handleError(ctx, err)
+	}
+
+	return res, nil
+}
+
+// Stream is the grpc.StreamServerInterceptor that logs the errors returned by stream handlers,
+// and converts them into gRPC statuses with ToStatus.
+func (i ServerInterceptor) Stream(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := handler(srv, ss); err != nil {
+		return i.handleError(ss.Context(), err)
+	}
+
+	return nil
+}
+
+func (i ServerInterceptor) handleError(ctx context.Context, err error) error {
+	i.logger.Error(ctx, err)
+	return ToStatus(err).Err()
+}
+
+// UnaryClientInterceptor is the grpc.UnaryClientInterceptor that converts the gRPC statuses returned by unary calls
+// into CustomErrors with FromError.
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return FromError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+// StreamClientInterceptor is the grpc.StreamClientInterceptor that converts the gRPC statuses returned by stream calls
+// into CustomErrors with FromError.
+func StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, FromError(err)
+	}
+
+	return clientStream{ClientStream: stream}, nil
+}
+
+// clientStream converts the errors of the wrapped grpc.ClientStream into CustomErrors.
+// io.EOF, which signals the end of the stream, is kept as is.
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s clientStream) SendMsg(m interface{}) error {
+	return FromError(s.ClientStream.SendMsg(m))
+}
+
+func (s clientStream) RecvMsg(m interface{}) error {
+	return FromError(s.ClientStream.RecvMsg(m))
+}
+
+func (s clientStream) CloseSend() error {
+	return FromError(s.ClientStream.CloseSend())
+}
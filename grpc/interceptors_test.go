@@ -0,0 +1,108 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ditointernet/go-dito/errors"
+	"github.com/ditointernet/go-dito/grpc"
+	"github.com/ditointernet/go-dito/grpc/mocks"
+)
+
+func TestNewServerInterceptor(t *testing.T) {
+	t.Run("should return an error when logger is not provided", func(t *testing.T) {
+		_, err := grpc.NewServerInterceptor(nil)
+
+		assert.EqualError(t, err, "missing required dependency: logger")
+	})
+}
+
+func TestServerInterceptorUnary(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should not touch successful responses", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		interceptor := grpc.MustNewServerInterceptor(mocks.NewMockLogger(ctrl))
+
+		res, err := interceptor.Unary(ctx, "request", &googlegrpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "response", nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "response", res)
+	})
+
+	t.Run("should log returned errors and convert them into statuses", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		handlerErr := errors.New("resource not found").WithKind(errors.KindNotFound)
+
+		logger := mocks.NewMockLogger(ctrl)
+		logger.EXPECT().Error(ctx, handlerErr)
+
+		interceptor := grpc.MustNewServerInterceptor(logger)
+
+		_, err := interceptor.Unary(ctx, "request", &googlegrpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, handlerErr
+		})
+
+		st, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.NotFound, st.Code())
+	})
+}
+
+type fakeServerStream struct {
+	googlegrpc.ServerStream
+	ctx context.Context
+}
+
+func (s fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestServerInterceptorStream(t *testing.T) {
+	t.Run("should log returned errors and convert them into statuses", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		handlerErr := errors.New("invalid input").WithKind(errors.KindInvalidInput)
+
+		logger := mocks.NewMockLogger(ctrl)
+		logger.EXPECT().Error(ctx, handlerErr)
+
+		interceptor := grpc.MustNewServerInterceptor(logger)
+
+		err := interceptor.Stream(nil, fakeServerStream{ctx: ctx}, &googlegrpc.StreamServerInfo{}, func(srv interface{}, stream googlegrpc.ServerStream) error {
+			return handlerErr
+		})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	t.Run("should rebuild CustomErrors from statuses", func(t *testing.T) {
+		serverErr := errors.New("resource not found").WithKind(errors.KindNotFound).WithCode("RESOURCE_NOT_FOUND")
+
+		err := grpc.UnaryClientInterceptor(context.Background(), "/svc/Method", nil, nil, nil,
+			func(ctx context.Context, method string, req, reply interface{}, cc *googlegrpc.ClientConn, opts ...googlegrpc.CallOption) error {
+				return grpc.ToStatus(serverErr).Err()
+			},
+		)
+
+		assert.EqualError(t, err, "resource not found")
+		assert.Equal(t, errors.KindNotFound, errors.Kind(err))
+		assert.Equal(t, errors.CodeType("RESOURCE_NOT_FOUND"), errors.Code(err))
+	})
+}
@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+// ErrorInfoDomain is the domain of the errdetails.ErrorInfo that carries the CodeType of a CustomError.
+const ErrorInfoDomain = "github.com/ditointernet/go-dito"
+
+// MetadataKeyKind is the errdetails.ErrorInfo metadata key that carries the KindType of a CustomError.
+const MetadataKeyKind = "kind"
+
+// ToStatus converts err into a gRPC status. Its code is the one registered for the error Kind, and its
+// CodeType, Kind and metadata fields are carried in an errdetails.ErrorInfo.
+// Errors that already are gRPC statuses are kept as is, and a nil error produces an OK status.
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	if st, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return st.GRPCStatus()
+	}
+
+	kind := errors.Kind(err)
+	code := codes.Code(errors.GRPCCodeOfKind(kind))
+	if code == codes.OK {
+		code = codes.Unknown
+	}
+
+	metadata := map[string]string{MetadataKeyKind: string(kind)}
+	for key, value := range errors.Fields(err) {
+		if key != MetadataKeyKind {
+			metadata[key] = fmt.Sprint(value)
+		}
+	}
+
+	st := status.New(code, err.Error())
+	detailed, detailsErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   string(errors.Code(err)),
+		Domain:   ErrorInfoDomain,
+		Metadata: metadata,
+	})
+	if detailsErr != nil {
+		return st
+	}
+
+	return detailed
+}
+
+// FromStatus converts a gRPC status into a CustomError. Kind, CodeType and metadata fields are read from its
+// errdetails.ErrorInfo, when there's one from ErrorInfoDomain, otherwise the Kind is the one registered for the
+// status code. It returns nil for OK statuses.
+func FromStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	err := errors.New("%s", st.Message()).WithKind(errors.KindFromGRPCCode(uint32(st.Code())))
+
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != ErrorInfoDomain {
+			continue
+		}
+
+		if info.GetReason() != "" {
+			err = err.WithCode(errors.CodeType(info.GetReason()))
+		}
+
+		for key, value := range info.GetMetadata() {
+			if key == MetadataKeyKind {
+				err = err.WithKind(errors.KindType(value))
+				continue
+			}
+
+			err = err.WithField(key, value)
+		}
+	}
+
+	return err
+}
+
+// FromError converts an error returned by a gRPC call into a CustomError. Check FromStatus for details.
+// Errors that aren't gRPC statuses are kept as is.
+func FromError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	return FromStatus(st)
+}
@@ -0,0 +1,88 @@
+package grpc_test
+
+import (
+	e "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ditointernet/go-dito/errors"
+	"github.com/ditointernet/go-dito/grpc"
+)
+
+func TestToStatus(t *testing.T) {
+	t.Run("should produce an OK status when there's no error", func(t *testing.T) {
+		assert.Equal(t, codes.OK, grpc.ToStatus(nil).Code())
+	})
+
+	t.Run("should keep errors that already are statuses", func(t *testing.T) {
+		st := grpc.ToStatus(status.Error(codes.Aborted, "aborted"))
+
+		assert.Equal(t, codes.Aborted, st.Code())
+		assert.Equal(t, "aborted", st.Message())
+	})
+
+	t.Run("should translate the kind into the status code and carry the code in the error info", func(t *testing.T) {
+		err := errors.New("resource not found").
+			WithKind(errors.KindNotFound).
+			WithCode("RESOURCE_NOT_FOUND").
+			WithField("entity_id", 123)
+
+		st := grpc.ToStatus(err)
+
+		assert.Equal(t, codes.NotFound, st.Code())
+		assert.Equal(t, "resource not found", st.Message())
+		assert.Len(t, st.Details(), 1)
+
+		info := st.Details()[0].(*errdetails.ErrorInfo)
+		assert.Equal(t, "RESOURCE_NOT_FOUND", info.GetReason())
+		assert.Equal(t, grpc.ErrorInfoDomain, info.GetDomain())
+		assert.Equal(t, map[string]string{"kind": "NOT_FOUND", "entity_id": "123"}, info.GetMetadata())
+	})
+
+	t.Run("should produce an unknown status for native errors", func(t *testing.T) {
+		assert.Equal(t, codes.Unknown, grpc.ToStatus(e.New("native error")).Code())
+	})
+}
+
+func TestFromStatus(t *testing.T) {
+	t.Run("should return nil for OK statuses", func(t *testing.T) {
+		assert.NoError(t, grpc.FromStatus(status.New(codes.OK, "")))
+	})
+
+	t.Run("should rebuild the CustomError converted by ToStatus", func(t *testing.T) {
+		original := errors.New("resource not found").
+			WithKind(errors.KindConflict).
+			WithCode("RESOURCE_NOT_FOUND").
+			WithField("entity_id", "123")
+
+		err := grpc.FromStatus(grpc.ToStatus(original))
+
+		assert.EqualError(t, err, "resource not found")
+		assert.Equal(t, errors.KindConflict, errors.Kind(err))
+		assert.Equal(t, errors.CodeType("RESOURCE_NOT_FOUND"), errors.Code(err))
+		assert.Equal(t, map[string]interface{}{"entity_id": "123"}, errors.Fields(err))
+	})
+
+	t.Run("should derive the kind from the status code when there's no error info", func(t *testing.T) {
+		err := grpc.FromStatus(status.New(codes.Unavailable, "unavailable"))
+
+		assert.Equal(t, errors.KindUnavailable, errors.Kind(err))
+		assert.Equal(t, errors.CodeUnknown, errors.Code(err))
+	})
+}
+
+func TestFromError(t *testing.T) {
+	t.Run("should keep errors that aren't statuses", func(t *testing.T) {
+		err := e.New("native error")
+
+		assert.Equal(t, err, grpc.FromError(err))
+	})
+
+	t.Run("should return nil when there's no error", func(t *testing.T) {
+		assert.NoError(t, grpc.FromError(nil))
+	})
+}
@@ -0,0 +1,495 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+const (
+	// CodeInvalidBody indicates that the request body is not a valid JSON for the bound struct
+	CodeInvalidBody errors.CodeType = "INVALID_BODY"
+	// CodeFieldInvalidType indicates that a field value can't be converted to the field type
+	CodeFieldInvalidType errors.CodeType = "FIELD_INVALID_TYPE"
+	// CodeFieldRequired indicates that a required field is missing
+	CodeFieldRequired errors.CodeType = "FIELD_REQUIRED"
+	// CodeFieldTooSmall indicates that a field value, or its length, is lower than the allowed minimum
+	CodeFieldTooSmall errors.CodeType = "FIELD_TOO_SMALL"
+	// CodeFieldTooLarge indicates that a field value, or its length, is greater than the allowed maximum
+	CodeFieldTooLarge errors.CodeType = "FIELD_TOO_LARGE"
+	// CodeFieldInvalidFormat indicates that a field value doesn't match the required pattern
+	CodeFieldInvalidFormat errors.CodeType = "FIELD_INVALID_FORMAT"
+	// CodeFieldInvalidOption indicates that a field value isn't one of the allowed options
+	CodeFieldInvalidOption errors.CodeType = "FIELD_INVALID_OPTION"
+)
+
+// FieldError is an error related to a specific field of a request.
+// Its field is exposed in the ErrorResponse and ErrorListResponse payloads.
+type FieldError struct {
+	Field string
+	Err   errors.CustomError
+}
+
+// NewFieldError creates a new FieldError of KindInvalidInput.
+func NewFieldError(field string, code errors.CodeType, message string, args ...interface{}) FieldError {
+	return FieldError{
+		Field: field,
+		Err:   errors.New(message, args...).WithKind(errors.KindInvalidInput).WithCode(code),
+	}
+}
+
+// Error returns the FieldError message.
+func (e FieldError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying CustomError.
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Bind decodes the JSON body, path params, query params and headers of the request into dst,
+// which must be a pointer to a struct, and then validates it. Params and headers take precedence over the body.
+//
+// The source of each field is defined by its tags:
+//
+//	type Input struct {
+//		ID     string   `path:"id" validate:"required"`
+//		Limit  int      `query:"limit" validate:"min=1,max=100"`
+//		Brand  string   `header:"Brand" validate:"required"`
+//		Status string   `json:"status" validate:"enum=active|inactive"`
+//		Tags   []string `json:"tags" validate:"max=10"`
+//	}
+//
+// Every violation found is returned at once as an ErrorListResponse, whose errors are FieldErrors.
+// Check Validate to know which rules are supported.
+func Bind(ctx *routing.Context, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("binding destination must be a pointer to a struct, got %T", dst))
+	}
+
+	supplied := fieldSet{}
+
+	if body := ctx.PostBody(); len(body) > 0 {
+		if err := json.Unmarshal(body, dst); err != nil {
+			return NewErrorListResponse(ctx, NewFieldError("body", CodeInvalidBody, "request body is invalid: %s", err))
+		}
+
+		var raw interface{}
+		if err := json.Unmarshal(body, &raw); err == nil {
+			supplied.addJSON(v.Elem().Type(), raw, "")
+		}
+	}
+
+	if errs := bindParams(ctx, v.Elem(), supplied); len(errs) > 0 {
+		return NewErrorListResponse(ctx, errs...)
+	}
+
+	if errs := validateStruct(v.Elem(), "", supplied); len(errs) > 0 {
+		return NewErrorListResponse(ctx, errs...)
+	}
+
+	return nil
+}
+
+// Validate checks v, a struct or a pointer to a struct, against the rules declared in the `validate` tag of its fields.
+// Nested structs, and slices of structs, are validated as well. The supported rules are:
+//
+//   - required: the field must not hold its zero value;
+//   - min=N and max=N: bounds the value of numbers, and the length of strings, slices and maps;
+//   - enum=a|b|c: the field value must be one of the given options;
+//   - regex=PATTERN: string fields must match the pattern. Since it may contain commas, it must be the last rule.
+//
+// Rules other than required are skipped when the field holds its zero value, since it can't be told apart
+// from an absent one. Use pointer fields to have zero values validated, or Bind, which knows the fields a request supplied.
+// Every violation found is returned at once as an ErrorListResponse, whose errors are FieldErrors.
+// It panics if any rule is malformed.
+func Validate(ctx context.Context, v interface{}) error {
+	errs := validateStruct(reflect.Indirect(reflect.ValueOf(v)), "", nil)
+	if len(errs) > 0 {
+		return NewErrorListResponse(ctx, errs...)
+	}
+
+	return nil
+}
+
+// fieldSet holds the names, as reported in FieldErrors, of the fields supplied by a request.
+type fieldSet map[string]bool
+
+// addJSON adds to the set the fields of t found in data, the generic decoding of a JSON body.
+func (s fieldSet) addJSON(t reflect.Type, data interface{}, prefix string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	obj, ok := data.(map[string]interface{})
+	if t.Kind() != reflect.Struct || !ok {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !promotesFields(field) {
+			continue
+		}
+
+		if promotesFields(field) {
+			s.addJSON(field.Type, data, prefix)
+			continue
+		}
+
+		name, fieldsPrefix := fieldPath(prefix, field)
+
+		key := strings.Split(field.Tag.Get("json"), ",")[0]
+		if key == "-" {
+			continue
+		}
+		if key == "" {
+			key = field.Name
+		}
+
+		value, ok := jsonValue(obj, key)
+		if !ok || value == nil {
+			continue
+		}
+
+		s[name] = true
+
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		switch items, _ := value.([]interface{}); {
+		case ft.Kind() == reflect.Struct:
+			s.addJSON(ft, value, fieldsPrefix)
+		case ft.Kind() == reflect.Slice:
+			for j, item := range items {
+				s.addJSON(ft.Elem(), item, fmt.Sprintf("%s[%d].", name, j))
+			}
+		}
+	}
+}
+
+// jsonValue looks key up in obj the way encoding/json does, preferring an exact match over a case-insensitive one.
+func jsonValue(obj map[string]interface{}, key string) (interface{}, bool) {
+	if value, ok := obj[key]; ok {
+		return value, true
+	}
+
+	for k, value := range obj {
+		if strings.EqualFold(k, key) {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+func bindParams(ctx *routing.Context, v reflect.Value, supplied fieldSet) []error {
+	var errs []error
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !promotesFields(field) {
+			continue
+		}
+
+		// Only embedded structs held by value are bound, since the others would have to be allocated beforehand.
+		if promotesFields(field) && field.Type.Kind() == reflect.Struct {
+			errs = append(errs, bindParams(ctx, v.Field(i), supplied)...)
+			continue
+		}
+
+		var name string
+		var raw [][]byte
+		if name = field.Tag.Get("path"); name != "" {
+			if param := ctx.Param(name); param != "" {
+				raw = [][]byte{[]byte(param)}
+			}
+		} else if name = field.Tag.Get("query"); name != "" {
+			raw = ctx.QueryArgs().PeekMulti(name)
+		} else if name = field.Tag.Get("header"); name != "" {
+			if header := ctx.Request.Header.Peek(name); len(header) > 0 {
+				raw = [][]byte{header}
+			}
+		}
+
+		if len(raw) == 0 {
+			continue
+		}
+
+		if err := setValue(v.Field(i), raw); err != nil {
+			errs = append(errs, NewFieldError(name, CodeFieldInvalidType, "%s has an invalid value: %s", name, err))
+			continue
+		}
+		path, _ := fieldPath("", field)
+		supplied[path] = true
+	}
+
+	return errs
+}
+
+func setValue(v reflect.Value, raw [][]byte) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := setValue(elem.Elem(), raw); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Slice:
+		slice := reflect.MakeSlice(v.Type(), len(raw), len(raw))
+		for i := range raw {
+			if err := setValue(slice.Index(i), raw[i:i+1]); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+
+	s := string(raw[0])
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("expected a boolean")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected an integer")
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected an unsigned integer")
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected a number")
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("params can't be bound into %s fields", v.Kind())
+	}
+
+	return nil
+}
+
+func validateStruct(v reflect.Value, prefix string, supplied fieldSet) []error {
+	var errs []error
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !promotesFields(field) {
+			continue
+		}
+
+		name, fieldsPrefix := fieldPath(prefix, field)
+		value := v.Field(i)
+
+		if err := validateField(value, name, field.Tag.Get("validate"), supplied[name]); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		value = reflect.Indirect(value)
+		switch {
+		case value.Kind() == reflect.Struct:
+			errs = append(errs, validateStruct(value, fieldsPrefix, supplied)...)
+		case value.Kind() == reflect.Slice && reflect.Indirect(reflect.New(value.Type().Elem())).Kind() == reflect.Struct:
+			for j := 0; j < value.Len(); j++ {
+				if item := reflect.Indirect(value.Index(j)); item.IsValid() {
+					errs = append(errs, validateStruct(item, fmt.Sprintf("%s[%d].", name, j), supplied)...)
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// fieldPath returns the name of field, as reported in FieldErrors, and the prefix of the names of its own fields.
+// Like encoding/json does, the fields of embedded structs without a JSON name are promoted to the struct that holds
+// them, so they share its prefix.
+func fieldPath(prefix string, field reflect.StructField) (name, fieldsPrefix string) {
+	name = prefix + fieldName(field)
+	if promotesFields(field) {
+		return name, prefix
+	}
+
+	return name, name + "."
+}
+
+// promotesFields reports whether field is an embedded struct whose fields are promoted, even if its type is unexported.
+func promotesFields(field reflect.StructField) bool {
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return field.Anonymous && t.Kind() == reflect.Struct && strings.Split(field.Tag.Get("json"), ",")[0] == ""
+}
+
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "path", "query", "header"} {
+		if name := strings.Split(field.Tag.Get(tag), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
+func validateField(v reflect.Value, name, tag string, supplied bool) error {
+	if tag == "" {
+		return nil
+	}
+
+	rules := parseRules(tag)
+	if _, ok := rules["required"]; ok && v.IsZero() {
+		return NewFieldError(name, CodeFieldRequired, "%s is required", name)
+	}
+
+	if v.IsZero() && !supplied {
+		return nil
+	}
+	v = reflect.Indirect(v)
+
+	if min, ok := rules["min"]; ok {
+		if measure(v, name) < parseBound(name, "min", min) {
+			return NewFieldError(name, CodeFieldTooSmall, "%s must be at least %s%s", name, min, measureUnit(v))
+		}
+	}
+
+	if max, ok := rules["max"]; ok {
+		if measure(v, name) > parseBound(name, "max", max) {
+			return NewFieldError(name, CodeFieldTooLarge, "%s must be at most %s%s", name, max, measureUnit(v))
+		}
+	}
+
+	if enum, ok := rules["enum"]; ok {
+		options := strings.Split(enum, "|")
+		value := fmt.Sprint(v.Interface())
+
+		allowed := false
+		for _, option := range options {
+			if option == value {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			return NewFieldError(name, CodeFieldInvalidOption, "%s must be one of [%s]", name, strings.Join(options, ", "))
+		}
+	}
+
+	if pattern, ok := rules["regex"]; ok {
+		if v.Kind() != reflect.String {
+			panic(fmt.Sprintf("regex rule of %s can only be applied to strings", name))
+		}
+
+		if !compileRegex(pattern).MatchString(v.String()) {
+			return NewFieldError(name, CodeFieldInvalidFormat, "%s must match the pattern %s", name, pattern)
+		}
+	}
+
+	return nil
+}
+
+func parseRules(tag string) map[string]string {
+	rules := map[string]string{}
+
+	for tag != "" {
+		var rule string
+		if strings.HasPrefix(tag, "regex=") {
+			rule, tag = tag, ""
+		} else if idx := strings.Index(tag, ","); idx >= 0 {
+			rule, tag = tag[:idx], tag[idx+1:]
+		} else {
+			rule, tag = tag, ""
+		}
+
+		kv := strings.SplitN(strings.TrimSpace(rule), "=", 2)
+		if len(kv) == 1 {
+			rules[kv[0]] = ""
+		} else {
+			rules[kv[0]] = kv[1]
+		}
+	}
+
+	return rules
+}
+
+func parseBound(name, rule, value string) float64 {
+	bound, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		panic(fmt.Sprintf("%s rule of %s must be a number, got %s", rule, name, value))
+	}
+
+	return bound
+}
+
+func measure(v reflect.Value, name string) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String()))
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len())
+	default:
+		panic(fmt.Sprintf("min and max rules can't be applied to %s, since it's a %s", name, v.Type()))
+	}
+}
+
+func measureUnit(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return " items"
+	default:
+		return ""
+	}
+}
+
+var regexCache sync.Map
+
+func compileRegex(pattern string) *regexp.Regexp {
+	if re, ok := regexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+
+	re := regexp.MustCompile(pattern)
+	regexCache.Store(pattern, re)
+
+	return re
+}
@@ -0,0 +1,217 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"net"
+	"net/http"
+	"testing"
+
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+type bindingAddress struct {
+	Street string `json:"street" validate:"required"`
+	State  string `json:"state" validate:"min=2,max=2"`
+}
+
+type bindingItem struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type bindingInput struct {
+	ID      string          `path:"id" validate:"required"`
+	Limit   int             `query:"limit" validate:"min=1,max=100"`
+	Tags    []string        `query:"tag" validate:"max=2"`
+	Brand   string          `header:"Brand" validate:"required"`
+	Status  string          `json:"status" validate:"enum=active|inactive"`
+	Email   string          `json:"email" validate:"regex=^[a-z]+@[a-z]+\\.com$"`
+	Address *bindingAddress `json:"address"`
+	Items   []bindingItem   `json:"items" validate:"max=3"`
+}
+
+type bindingPagination struct {
+	Limit int `query:"limit" validate:"min=1"`
+}
+
+type bindingEntity struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func newBindingCtx(body string, params, headers map[string]string, query string) *routing.Context {
+	reqCtx := &fasthttp.RequestCtx{}
+	req := fasthttp.AcquireRequest()
+	req.SetRequestURI("http://test/route?" + query)
+	req.SetBodyString(body)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	reqCtx.Init(req, &net.IPAddr{IP: net.IPv4(127, 0, 0, 1)}, nil)
+
+	ctx := &routing.Context{RequestCtx: reqCtx}
+	for key, value := range params {
+		ctx.SetParam(key, value)
+	}
+
+	return ctx
+}
+
+func TestBind(t *testing.T) {
+	t.Run("should bind path params, query params, headers and body into the struct", func(t *testing.T) {
+		ctx := newBindingCtx(
+			`{"status":"active","email":"john@dito.com","address":{"street":"Rua A","state":"MG"},"items":[{"name":"item"}]}`,
+			map[string]string{"id": "123"},
+			map[string]string{"Brand": "a-brand"},
+			"limit=10&tag=a&tag=b",
+		)
+
+		var in bindingInput
+		assert.NoError(t, Bind(ctx, &in))
+		assert.Equal(t, bindingInput{
+			ID:      "123",
+			Limit:   10,
+			Tags:    []string{"a", "b"},
+			Brand:   "a-brand",
+			Status:  "active",
+			Email:   "john@dito.com",
+			Address: &bindingAddress{Street: "Rua A", State: "MG"},
+			Items:   []bindingItem{{Name: "item"}},
+		}, in)
+	})
+
+	t.Run("should return every violation at once", func(t *testing.T) {
+		ctx := newBindingCtx(
+			`{"status":"deleted","email":"JOHN","address":{"state":"MGA"},"items":[{"name":"item"},{}]}`,
+			nil,
+			nil,
+			"limit=1000&tag=a&tag=b&tag=c",
+		)
+
+		var in bindingInput
+		err := Bind(ctx, &in)
+
+		var res ErrorListResponse
+		assert.True(t, stdErrors.As(err, &res))
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode())
+		assert.Equal(t, []errorPayload{
+			{Code: CodeFieldRequired, Message: "id is required", Field: "id"},
+			{Code: CodeFieldTooLarge, Message: "limit must be at most 100", Field: "limit"},
+			{Code: CodeFieldTooLarge, Message: "tag must be at most 2 items", Field: "tag"},
+			{Code: CodeFieldRequired, Message: "Brand is required", Field: "Brand"},
+			{Code: CodeFieldInvalidOption, Message: "status must be one of [active, inactive]", Field: "status"},
+			{Code: CodeFieldInvalidFormat, Message: `email must match the pattern ^[a-z]+@[a-z]+\.com$`, Field: "email"},
+			{Code: CodeFieldRequired, Message: "address.street is required", Field: "address.street"},
+			{Code: CodeFieldTooLarge, Message: "address.state must be at most 2 characters", Field: "address.state"},
+			{Code: CodeFieldRequired, Message: "items[1].name is required", Field: "items[1].name"},
+		}, res.Errs)
+	})
+
+	t.Run("should validate zero values supplied by the request", func(t *testing.T) {
+		ctx := newBindingCtx(
+			`{"status":"","address":{"street":"Rua A","state":""}}`,
+			map[string]string{"id": "123"},
+			map[string]string{"Brand": "a-brand"},
+			"limit=0",
+		)
+
+		var in bindingInput
+		err := Bind(ctx, &in)
+
+		var res ErrorListResponse
+		assert.True(t, stdErrors.As(err, &res))
+		assert.Equal(t, []errorPayload{
+			{Code: CodeFieldTooSmall, Message: "limit must be at least 1", Field: "limit"},
+			{Code: CodeFieldInvalidOption, Message: "status must be one of [active, inactive]", Field: "status"},
+			{Code: CodeFieldTooSmall, Message: "address.state must be at least 2 characters", Field: "address.state"},
+		}, res.Errs)
+	})
+
+	t.Run("should report the fields of embedded structs by their promoted names", func(t *testing.T) {
+		type input struct {
+			bindingPagination
+			bindingEntity
+			Entity bindingEntity `json:"entity"`
+			Status string        `json:"status" validate:"enum=active|inactive"`
+		}
+
+		ctx := newBindingCtx(`{"entity":{"name":""},"name":"","status":""}`, nil, nil, "limit=0")
+
+		var in input
+		err := Bind(ctx, &in)
+
+		var res ErrorListResponse
+		assert.True(t, stdErrors.As(err, &res))
+		assert.Equal(t, []errorPayload{
+			{Code: CodeFieldTooSmall, Message: "limit must be at least 1", Field: "limit"},
+			{Code: CodeFieldRequired, Message: "name is required", Field: "name"},
+			{Code: CodeFieldRequired, Message: "entity.name is required", Field: "entity.name"},
+			{Code: CodeFieldInvalidOption, Message: "status must be one of [active, inactive]", Field: "status"},
+		}, res.Errs)
+	})
+
+	t.Run("should return an invalid type error when a param can't be bound into its field", func(t *testing.T) {
+		type input struct {
+			Filters map[string]string `query:"filters"`
+		}
+
+		ctx := newBindingCtx("", nil, nil, "filters=a")
+
+		var in input
+		err := Bind(ctx, &in)
+
+		var res ErrorListResponse
+		assert.True(t, stdErrors.As(err, &res))
+		assert.Equal(t, []errorPayload{
+			{Code: CodeFieldInvalidType, Message: "filters has an invalid value: params can't be bound into map fields", Field: "filters"},
+		}, res.Errs)
+	})
+
+	t.Run("should return an invalid type error when a param can't be converted", func(t *testing.T) {
+		ctx := newBindingCtx("", map[string]string{"id": "123"}, map[string]string{"Brand": "a"}, "limit=ten")
+
+		var in bindingInput
+		err := Bind(ctx, &in)
+
+		var res ErrorListResponse
+		assert.True(t, stdErrors.As(err, &res))
+		assert.Equal(t, []errorPayload{
+			{Code: CodeFieldInvalidType, Message: "limit has an invalid value: expected an integer", Field: "limit"},
+		}, res.Errs)
+	})
+
+	t.Run("should return an invalid body error when the body isn't a valid JSON", func(t *testing.T) {
+		ctx := newBindingCtx("{", nil, nil, "")
+
+		var in bindingInput
+		err := Bind(ctx, &in)
+
+		var res ErrorListResponse
+		assert.True(t, stdErrors.As(err, &res))
+		assert.Equal(t, CodeInvalidBody, res.Errs[0].Code)
+		assert.Equal(t, errors.KindInvalidInput, res.kind)
+	})
+
+	t.Run("should panic when the destination isn't a pointer to a struct", func(t *testing.T) {
+		assert.Panics(t, func() {
+			Bind(newBindingCtx("", nil, nil, ""), bindingInput{})
+		})
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("should return nil when there is no violation", func(t *testing.T) {
+		assert.NoError(t, Validate(context.Background(), bindingAddress{Street: "Rua A"}))
+	})
+
+	t.Run("should expose the violated field in the error payload", func(t *testing.T) {
+		err := Validate(context.Background(), bindingAddress{})
+
+		body, _ := json.Marshal(err)
+		assert.JSONEq(t, `{"errors":[{"code":"FIELD_REQUIRED","message":"street is required","field":"street"}]}`, string(body))
+	})
+}
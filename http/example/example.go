@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	net "net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
 	routing "github.com/jackwhelpton/fasthttp-routing/v2"
 
@@ -18,6 +22,12 @@ func main() {
 	server := http.NewServer(http.ServerInput{
 		Port:   3000,
 		Logger: logger,
+		OnShutdown: []http.Hook{
+			func(ctx context.Context) error {
+				// Flush tracers, close Pub/Sub clients, etc.
+				return nil
+			},
+		},
 		Handler: func(r *routing.Router) {
 			r.Get("/route1", func(ctx *routing.Context) error {
 				return http.NewErrorResponse(ctx, errors.New("random error").
@@ -74,10 +84,14 @@ func main() {
 		},
 	})
 
-	server.Run()
+	// Runs until a SIGINT or SIGTERM is received, then gracefully shuts the server down
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server.RunContext(ctx)
 
 	// How to handle a request in memory
-	
+
 	req, err := net.NewRequest("GET", "http://any-url-test/your-route", nil)
 	if err != nil {
 		return
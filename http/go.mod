@@ -1,22 +1,26 @@
 module github.com/ditointernet/go-dito/http
 
-go 1.14
+go 1.18
 
 require (
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/ditointernet/go-dito/errors v1.0.0
 	github.com/ditointernet/go-dito/log v1.0.0
-	github.com/felixge/httpsnoop v1.0.2 // indirect
 	github.com/golang/mock v1.5.0
 	github.com/jackwhelpton/fasthttp-routing/v2 v2.0.0
-	github.com/klauspost/compress v1.13.5 // indirect
-	github.com/kr/text v0.2.0 // indirect
-	github.com/niemeyer/pretty v0.0.0-20200227124842-a10e7caefd8e // indirect
+	github.com/prometheus/client_golang v1.16.0
 	github.com/rs/cors v1.7.0
-	github.com/stretchr/testify v1.7.0
+	github.com/stretchr/testify v1.8.3
 	github.com/valyala/fasthttp v1.24.0
-	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.20.0
-	go.opentelemetry.io/otel/trace v0.20.0
-	golang.org/x/text v0.3.6 // indirect
-	gopkg.in/check.v1 v1.0.0-20200227125254-8fa46927fb4f // indirect
+	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.42.0
+	go.opentelemetry.io/otel v1.16.0
+	go.opentelemetry.io/otel/metric v1.16.0
+	go.opentelemetry.io/otel/sdk/metric v0.39.0
+	go.opentelemetry.io/otel/trace v1.16.0
+)
+
+require (
+	github.com/klauspost/compress v1.13.5 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	go.opentelemetry.io/otel/sdk v1.16.0
 )
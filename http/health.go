@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+const (
+	// LivenessPath is the route which reports whether the Server is alive.
+	LivenessPath = "/healthz"
+	// ReadinessPath is the route which reports whether the Server is ready to receive requests.
+	ReadinessPath = "/readyz"
+)
+
+const (
+	// HealthStatusOK indicates that a health check has succeeded.
+	HealthStatusOK = "OK"
+	// HealthStatusFailing indicates that a health check has failed.
+	HealthStatusFailing = "FAILING"
+)
+
+// CodeHealthCheckTimeout indicates that a health check didn't finish within the HealthCheckTimeout of the Server
+const CodeHealthCheckTimeout errors.CodeType = "HEALTH_CHECK_TIMEOUT"
+
+const defaultHealthCheckTimeout = 5 * time.Second
+
+// HealthChecker is implemented by dependencies that are able to report their own health.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// HealthCheckerFunc adapts an ordinary function into a HealthChecker.
+type HealthCheckerFunc func(ctx context.Context) error
+
+// HealthCheck calls f(ctx).
+func (f HealthCheckerFunc) HealthCheck(ctx context.Context) error {
+	return f(ctx)
+}
+
+// HealthCheckResult is the outcome of a single health check.
+// Since the readiness route is usually public, errors are exposed only by their code, when they have one,
+// while their full message is logged.
+type HealthCheckResult struct {
+	Status string          `json:"status"`
+	Code   errors.CodeType `json:"code,omitempty"`
+}
+
+// HealthResponse is the response sent by the liveness and readiness routes.
+type HealthResponse struct {
+	Message string                       `json:"message"`
+	Checks  map[string]HealthCheckResult `json:"checks,omitempty"`
+}
+
+func (s Server) addHealthRoutes() {
+	s.router.Get(LivenessPath, func(ctx *routing.Context) error {
+		ctx.SetStatusCode(http.StatusOK)
+		return ctx.Write(HealthResponse{Message: HealthStatusOK})
+	})
+
+	s.router.Get(ReadinessPath, func(ctx *routing.Context) error {
+		res, ok := s.checkReadiness(ctx)
+		if ok {
+			ctx.SetStatusCode(http.StatusOK)
+		} else {
+			ctx.SetStatusCode(http.StatusServiceUnavailable)
+		}
+
+		return ctx.Write(res)
+	})
+}
+
+func (s Server) checkReadiness(ctx context.Context) (HealthResponse, bool) {
+	if s.IsShuttingDown() {
+		return HealthResponse{Message: "server is shutting down"}, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.healthCheckTimeout)
+	defer cancel()
+
+	res := HealthResponse{Message: HealthStatusOK, Checks: map[string]HealthCheckResult{}}
+	ok := true
+
+	var mux sync.Mutex
+	var wg sync.WaitGroup
+	for name, checker := range s.healthCheckers {
+		wg.Add(1)
+		go func(name string, checker HealthChecker) {
+			defer wg.Done()
+
+			result := HealthCheckResult{Status: HealthStatusOK}
+			if err := s.runHealthCheck(ctx, checker); err != nil {
+				result = HealthCheckResult{Status: HealthStatusFailing}
+				if code := errors.Code(err); code != errors.CodeUnknown {
+					result.Code = code
+				}
+
+				if s.logger != nil {
+					s.logger.Error(ctx, errors.Wrapf(err, "health check %s failed", name))
+				}
+			}
+
+			mux.Lock()
+			defer mux.Unlock()
+			res.Checks[name] = result
+			if result.Status != HealthStatusOK {
+				ok = false
+			}
+		}(name, checker)
+	}
+	wg.Wait()
+
+	if !ok {
+		res.Message = "one or more health checks have failed"
+	}
+
+	return res, ok
+}
+
+// runHealthCheck runs checker until ctx is done, so checkers that ignore ctx don't hold the readiness route.
+func (s Server) runHealthCheck(ctx context.Context, checker HealthChecker) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- checker.HealthCheck(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.New("health check timed out: %s", ctx.Err()).WithKind(errors.KindTimeout).WithCode(CodeHealthCheckTimeout)
+	}
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	ditoErrors "github.com/ditointernet/go-dito/errors"
+	"github.com/ditointernet/go-dito/http/mocks"
+)
+
+func doInMemoryRequest(t *testing.T, server Server, method, url string) (int, []byte) {
+	req, err := http.NewRequest(method, url, nil)
+	assert.NoError(t, err)
+
+	res, err := server.HandleRequestInMemory(req)
+	assert.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+
+	return res.StatusCode, body
+}
+
+func TestHealthRoutes(t *testing.T) {
+	healthy := HealthCheckerFunc(func(ctx context.Context) error { return nil })
+	unhealthy := HealthCheckerFunc(func(ctx context.Context) error { return errors.New("unreachable") })
+
+	t.Run("should report the server as alive", func(t *testing.T) {
+		server := newTestServer(ServerInput{
+			HealthCheckers: map[string]HealthChecker{"db": unhealthy},
+		})
+
+		status, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/healthz")
+
+		assert.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `{"message":"OK"}`, string(body))
+	})
+
+	t.Run("should report the server as ready when all checks succeed", func(t *testing.T) {
+		server := newTestServer(ServerInput{
+			HealthCheckers: map[string]HealthChecker{"db": healthy, "cache": healthy},
+		})
+
+		status, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/readyz")
+
+		assert.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `{"message":"OK","checks":{"db":{"status":"OK"},"cache":{"status":"OK"}}}`, string(body))
+	})
+
+	t.Run("should report the server as not ready when any check fails", func(t *testing.T) {
+		server := newTestServer(ServerInput{
+			HealthCheckers: map[string]HealthChecker{"db": healthy, "cache": unhealthy},
+		})
+
+		status, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/readyz")
+
+		var res HealthResponse
+		assert.NoError(t, json.Unmarshal(body, &res))
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+		assert.Equal(t, HealthCheckResult{Status: HealthStatusFailing}, res.Checks["cache"])
+		assert.NotContains(t, string(body), "unreachable")
+	})
+
+	t.Run("should expose only the code of failing checks and log their errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		logger := mocks.NewMockLogger(ctrl)
+		logger.EXPECT().Info(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		logger.EXPECT().Error(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, err error) {
+			assert.EqualError(t, err, "health check db failed: dial tcp 10.0.0.1:5432: connection refused")
+		})
+
+		server := newTestServer(ServerInput{
+			Logger: logger,
+			HealthCheckers: map[string]HealthChecker{"db": HealthCheckerFunc(func(ctx context.Context) error {
+				return ditoErrors.New("dial tcp 10.0.0.1:5432: connection refused").WithCode("DATABASE_UNREACHABLE")
+			})},
+		})
+
+		_, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/readyz")
+
+		assert.JSONEq(t, `{"message":"one or more health checks have failed","checks":{"db":{"status":"FAILING","code":"DATABASE_UNREACHABLE"}}}`, string(body))
+	})
+
+	t.Run("should not wait for checks that ignore the timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		server := newTestServer(ServerInput{
+			HealthCheckTimeout: 10 * time.Millisecond,
+			HealthCheckers: map[string]HealthChecker{"stuck": HealthCheckerFunc(func(ctx context.Context) error {
+				<-release
+				return nil
+			})},
+		})
+
+		status, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/readyz")
+
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+		assert.JSONEq(t, `{"message":"one or more health checks have failed","checks":{"stuck":{"status":"FAILING","code":"HEALTH_CHECK_TIMEOUT"}}}`, string(body))
+	})
+
+	t.Run("should report the server as not ready while it is shutting down", func(t *testing.T) {
+		server := newTestServer(ServerInput{})
+		assert.NoError(t, server.Shutdown(context.Background()))
+
+		status, _ := doInMemoryRequest(t, server, http.MethodGet, "http://test/readyz")
+
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+	})
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+// acceptedLanguages returns the languages of the request Accept-Language header, sorted by their quality.
+func acceptedLanguages(ctx context.Context) []string {
+	c, ok := ctx.(*routing.Context)
+	if !ok || c.RequestCtx == nil {
+		return nil
+	}
+
+	return parseAcceptLanguage(string(c.Request.Header.Peek("Accept-Language")))
+}
+
+func parseAcceptLanguage(header string) []string {
+	type language struct {
+		tag     string
+		quality float64
+	}
+
+	var languages []language
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(params[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+
+			if value, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+				quality = value
+			}
+		}
+
+		if quality > 0 {
+			languages = append(languages, language{tag: tag, quality: quality})
+		}
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+
+	tags := make([]string, len(languages))
+	for i, language := range languages {
+		tags[i] = language.tag
+	}
+
+	return tags
+}
+
+// localizedMessage returns the message of err in the first of the locales available at errors.DefaultCatalog,
+// or the message of err itself when its code isn't registered there.
+func localizedMessage(err error, locales []string) string {
+	if msg, ok := errors.LocalizedMessage(err, locales...); ok {
+		return msg
+	}
+
+	return err.Error()
+}
+
+// errorStatusCode returns the HTTP status registered at errors.DefaultCatalog for the code of err,
+// as long as err keeps the kind of its code, or the HTTP status of its kind otherwise.
+func errorStatusCode(err error) int {
+	kind := errors.Kind(err)
+	if entry, ok := errors.DefaultCatalog.Lookup(errors.Code(err)); ok && entry.Kind == kind {
+		return entry.HTTPStatus
+	}
+
+	return kindToHTTPStatusCode(kind)
+}
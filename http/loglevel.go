@@ -0,0 +1,55 @@
+package http
+
+import (
+	"crypto/subtle"
+
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+
+	"github.com/ditointernet/go-dito/log"
+)
+
+const (
+	// LogLevelPath is the route which serves the log level on GET, and changes it on PUT,
+	// using a {"level": "DEBUG"} JSON body. It's only mounted when a LogLevel is given to the Server,
+	// and every request goes through its LogLevelGuard first.
+	LogLevelPath = "/loglevel"
+	// LogLevelSecretHeader is the request header that must hold the LogLevelHeaderSecret of the Server
+	// for the log level of the request to be overridden.
+	LogLevelSecretHeader = "X-Log-Level-Secret"
+)
+
+func (s Server) addLogLevelRoute(level *log.AtomicLevel, guard routing.Handler) {
+	if guard == nil {
+		panic("a LogLevelGuard is required to serve the LogLevel")
+	}
+
+	handler := routing.RequestHandlerFunc(fasthttpadaptor.NewFastHTTPHandler(level))
+	s.router.Get(LogLevelPath, guard, handler)
+	s.router.Put(LogLevelPath, guard, handler)
+}
+
+// addLogLevelOverride makes the requests that carry a valid level name in the given header be logged
+// according to it, instead of the Logger level, as long as they also carry the secret in LogLevelSecretHeader.
+func (s Server) addLogLevelOverride(header, secret string) {
+	if secret == "" {
+		panic("a LogLevelHeaderSecret is required to override the log level by the LogLevelHeader")
+	}
+
+	s.router.Use(func(ctx *routing.Context) error {
+		name := ctx.Request.Header.Peek(header)
+		if len(name) == 0 {
+			return nil
+		}
+
+		if subtle.ConstantTimeCompare(ctx.Request.Header.Peek(LogLevelSecretHeader), []byte(secret)) != 1 {
+			return nil
+		}
+
+		if level, err := log.ParseLevel(string(name)); err == nil {
+			ctx.SetUserValue(log.ContextKeyLevel, level)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+	"github.com/stretchr/testify/assert"
+
+	ditoErrors "github.com/ditointernet/go-dito/errors"
+	"github.com/ditointernet/go-dito/log"
+)
+
+// adminGuard only allows the requests with an "Authorization: admin" header.
+func adminGuard(ctx *routing.Context) error {
+	if string(ctx.Request.Header.Peek("Authorization")) != "admin" {
+		return NewErrorResponse(ctx, ditoErrors.New("not allowed").WithKind(ditoErrors.KindUnauthorized))
+	}
+
+	return nil
+}
+
+func TestLogLevelRoute(t *testing.T) {
+	doRequest := func(t *testing.T, server Server, method, auth, body string) (int, []byte) {
+		req, err := http.NewRequest(method, "http://test/loglevel", strings.NewReader(body))
+		assert.NoError(t, err)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+
+		res, err := server.HandleRequestInMemory(req)
+		assert.NoError(t, err)
+		defer res.Body.Close()
+
+		resBody, err := ioutil.ReadAll(res.Body)
+		assert.NoError(t, err)
+
+		return res.StatusCode, resBody
+	}
+
+	t.Run("should serve the log level", func(t *testing.T) {
+		server := newTestServer(ServerInput{LogLevel: log.NewAtomicLevel(log.LevelWarning), LogLevelGuard: adminGuard})
+
+		status, body := doRequest(t, server, http.MethodGet, "admin", "")
+
+		assert.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `{"level":"WARNING"}`, string(body))
+	})
+
+	t.Run("should change the log level", func(t *testing.T) {
+		level := log.NewAtomicLevel(log.LevelWarning)
+		server := newTestServer(ServerInput{LogLevel: level, LogLevelGuard: adminGuard})
+
+		status, body := doRequest(t, server, http.MethodPut, "admin", `{"level":"DEBUG"}`)
+
+		assert.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `{"level":"DEBUG"}`, string(body))
+		assert.Equal(t, log.LevelDebug, level.Level())
+	})
+
+	t.Run("should reject the requests the guard doesn't allow", func(t *testing.T) {
+		level := log.NewAtomicLevel(log.LevelWarning)
+		server := newTestServer(ServerInput{LogLevel: level, LogLevelGuard: adminGuard})
+
+		status, body := doRequest(t, server, http.MethodPut, "", `{"level":"DEBUG"}`)
+
+		assert.Equal(t, http.StatusForbidden, status)
+		assert.NotContains(t, string(body), "level")
+		assert.Equal(t, log.LevelWarning, level.Level())
+	})
+
+	t.Run("should panic when no guard is given", func(t *testing.T) {
+		assert.Panics(t, func() {
+			newTestServer(ServerInput{LogLevel: log.NewAtomicLevel(log.LevelWarning)})
+		})
+	})
+
+	t.Run("should not be served when no log level is given", func(t *testing.T) {
+		server := newTestServer(ServerInput{})
+
+		status, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/loglevel")
+
+		assert.NotEqual(t, http.StatusOK, status)
+		assert.NotContains(t, string(body), "level")
+	})
+}
+
+func TestLogLevelOverride(t *testing.T) {
+	var level interface{}
+	server := newTestServer(ServerInput{
+		LogLevelHeader:       "X-Log-Level",
+		LogLevelHeaderSecret: "secret",
+		Handler: func(r *routing.Router) {
+			r.Get("/test", func(ctx *routing.Context) error {
+				level = ctx.Value(log.ContextKeyLevel)
+				return nil
+			})
+		},
+	})
+
+	tt := []struct {
+		desc          string
+		header        string
+		secret        string
+		expectedLevel interface{}
+	}{
+		{
+			desc:          "should override the log level of requests with the header and the secret",
+			header:        "debug",
+			secret:        "secret",
+			expectedLevel: log.LevelDebug,
+		},
+		{
+			desc:          "should ignore requests without the secret",
+			header:        "debug",
+			expectedLevel: nil,
+		},
+		{
+			desc:          "should ignore requests with a wrong secret",
+			header:        "debug",
+			secret:        "guess",
+			expectedLevel: nil,
+		},
+		{
+			desc:          "should ignore unknown levels",
+			header:        "TRACE",
+			secret:        "secret",
+			expectedLevel: nil,
+		},
+		{
+			desc:          "should ignore requests without the header",
+			expectedLevel: nil,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			level = nil
+
+			req, err := http.NewRequest(http.MethodGet, "http://test/test", nil)
+			assert.NoError(t, err)
+			if tc.header != "" {
+				req.Header.Set("X-Log-Level", tc.header)
+			}
+			if tc.secret != "" {
+				req.Header.Set(LogLevelSecretHeader, tc.secret)
+			}
+
+			res, err := server.HandleRequestInMemory(req)
+			assert.NoError(t, err)
+			res.Body.Close()
+
+			assert.Equal(t, tc.expectedLevel, level)
+		})
+	}
+}
+
+func TestLogLevelOverrideWithoutSecret(t *testing.T) {
+	assert.Panics(t, func() {
+		newTestServer(ServerInput{LogLevelHeader: "X-Log-Level"})
+	})
+}
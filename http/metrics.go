@@ -0,0 +1,113 @@
+package http
+
+import (
+	"time"
+
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+// MetricsPath is the route which serves metrics in the Prometheus exposition format,
+// when a MetricsGatherer is given to the Server.
+const MetricsPath = "/metrics"
+
+const instrumentationName = "github.com/ditointernet/go-dito/http"
+
+// Metric attribute keys recorded by the Server on every request.
+const (
+	AttributeKeyMethod     = attribute.Key("http.method")
+	AttributeKeyRoute      = attribute.Key("http.route")
+	AttributeKeyStatusCode = attribute.Key("http.status_code")
+	AttributeKeyErrorKind  = attribute.Key("error.kind")
+)
+
+type serverMetrics struct {
+	requests     metric.Int64Counter
+	duration     metric.Float64Histogram
+	responseSize metric.Int64Histogram
+}
+
+func newServerMetrics(provider metric.MeterProvider) (serverMetrics, error) {
+	meter := provider.Meter(instrumentationName)
+
+	requests, err := meter.Int64Counter(
+		"http.server.request_count",
+		metric.WithDescription("Number of HTTP requests handled by the server"),
+	)
+	if err != nil {
+		return serverMetrics{}, err
+	}
+
+	duration, err := meter.Float64Histogram(
+		"http.server.duration",
+		metric.WithDescription("Duration of HTTP requests handled by the server"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return serverMetrics{}, err
+	}
+
+	responseSize, err := meter.Int64Histogram(
+		"http.server.response.size",
+		metric.WithDescription("Size of HTTP response bodies sent by the server"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return serverMetrics{}, err
+	}
+
+	return serverMetrics{requests: requests, duration: duration, responseSize: responseSize}, nil
+}
+
+func (s Server) addMetricsMiddleware(provider metric.MeterProvider) {
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+
+	metrics, err := newServerMetrics(provider)
+	if err != nil {
+		otel.Handle(err)
+		return
+	}
+
+	s.router.Use(func(ctx *routing.Context) error {
+		start := time.Now()
+		err := ctx.Next()
+		elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+
+		status := ctx.Response.StatusCode()
+		if httpErr, ok := err.(routing.HTTPError); ok {
+			status = httpErr.StatusCode()
+		}
+
+		attrs := []attribute.KeyValue{
+			AttributeKeyMethod.String(string(ctx.Method())),
+			AttributeKeyStatusCode.Int(status),
+		}
+		if route := s.routes.resolve(string(ctx.Method()), string(ctx.Path())); route != "" {
+			attrs = append(attrs, AttributeKeyRoute.String(route))
+		}
+		if kind, ok := ctx.UserValue(contextKeyErrorKind).(errors.KindType); ok {
+			attrs = append(attrs, AttributeKeyErrorKind.String(string(kind)))
+		}
+
+		opt := metric.WithAttributes(attrs...)
+		metrics.requests.Add(ctx, 1, opt)
+		metrics.duration.Record(ctx, elapsed, opt)
+		metrics.responseSize.Record(ctx, int64(len(ctx.Response.Body())), opt)
+
+		return err
+	})
+}
+
+func (s Server) addMetricsRoute(gatherer prometheus.Gatherer) {
+	handler := promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+	s.router.Get(MetricsPath, routing.RequestHandlerFunc(fasthttpadaptor.NewFastHTTPHandler(handler)))
+}
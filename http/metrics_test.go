@@ -0,0 +1,78 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+func collectRequestCount(t *testing.T, reader sdkmetric.Reader) []metricdata.DataPoint[int64] {
+	var rm metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &rm))
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == "http.server.request_count" {
+				return m.Data.(metricdata.Sum[int64]).DataPoints
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	t.Run("should record requests labeled by route template, status code and error kind", func(t *testing.T) {
+		reader := sdkmetric.NewManualReader()
+
+		server := newTestServer(ServerInput{
+			MeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+			Handler: func(r *routing.Router) {
+				r.Get("/users/<id>", func(ctx *routing.Context) error {
+					return errors.New("user not found").WithKind(errors.KindNotFound)
+				})
+			},
+		})
+
+		status, _ := doInMemoryRequest(t, server, http.MethodGet, "http://test/users/123")
+		assert.Equal(t, http.StatusNotFound, status)
+
+		points := collectRequestCount(t, reader)
+		assert.Len(t, points, 1)
+		assert.Equal(t, int64(1), points[0].Value)
+
+		expected := attribute.NewSet(
+			AttributeKeyMethod.String(http.MethodGet),
+			AttributeKeyRoute.String("/users/<id>"),
+			AttributeKeyStatusCode.Int(http.StatusNotFound),
+			AttributeKeyErrorKind.String(string(errors.KindNotFound)),
+		)
+		assert.True(t, expected.Equals(&points[0].Attributes))
+	})
+}
+
+func TestMetricsRoute(t *testing.T) {
+	t.Run("should serve metrics in the Prometheus exposition format", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "random_counter", Help: "A random counter"})
+		registry.MustRegister(counter)
+		counter.Inc()
+
+		server := newTestServer(ServerInput{MetricsGatherer: registry})
+
+		status, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/metrics")
+
+		assert.Equal(t, http.StatusOK, status)
+		assert.True(t, strings.Contains(string(body), "random_counter 1"))
+	})
+}
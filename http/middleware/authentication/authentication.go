@@ -23,6 +23,18 @@ const (
 	CodeTypeErrorOnParsingJWTToken errors.CodeType = "COULD_NOT_HANDLE_TOKEN"
 )
 
+func init() {
+	errors.MustRegisterCode(errors.CatalogEntry{
+		Code:        CodeTypeMissingBearerToken,
+		Kind:        errors.KindUnauthenticated,
+		Description: "The Authorization header doesn't carry a bearer token",
+		Messages: map[string]string{
+			"en":    "missing or invalid authentication token",
+			"pt-BR": "token de autenticação ausente ou inválido",
+		},
+	})
+}
+
 // AccountAuthenticator structure responsible for handling request authentication
 type AccountAuthenticator struct {
 	logger infra.Logger
@@ -64,7 +76,7 @@ func MustNewAccountAuthenticator(logger infra.Logger, jwks infra.JWKSClient) Acc
 func (ua AccountAuthenticator) Authenticate(ctx *routing.Context) error {
 	authHeader := string(ctx.Request.Header.Peek("Authorization"))
 	if len(authHeader) < 7 || strings.ToLower(authHeader[:7]) != "bearer " || authHeader[7:] == "" {
-		err := errors.New("missing or invalid authentication token").WithKind(errors.KindUnauthenticated).WithCode(CodeTypeMissingBearerToken)
+		err := errors.FromCode(CodeTypeMissingBearerToken, nil)
 		ua.logger.Error(ctx, err)
 		return http.NewErrorResponse(ctx, err)
 	}
@@ -87,7 +99,7 @@ func (ua AccountAuthenticator) Authenticate(ctx *routing.Context) error {
 	parsedToken, err = jwt.Parse(token, verifyJWTSignature(certs))
 	if err != nil {
 		ua.logger.Error(ctx, err)
-		err = errors.New(err.Error()).WithKind(errors.KindUnauthenticated).WithCode(CodeTypeErrorOnParsingJWTToken)
+		err = errors.Wrap(err, "").WithKind(errors.KindUnauthenticated).WithCode(CodeTypeErrorOnParsingJWTToken)
 		return http.NewErrorResponse(ctx, err)
 	}
 
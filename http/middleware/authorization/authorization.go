@@ -30,6 +30,18 @@ const (
 	CodeTypeAccessDenied errors.CodeType = "ACCESS_DENIED"
 )
 
+func init() {
+	errors.MustRegisterCode(errors.CatalogEntry{
+		Code:        CodeTypeAccessDenied,
+		Kind:        errors.KindUnauthorized,
+		Description: "The account is not allowed to access the resource on behalf of the brand",
+		Messages: map[string]string{
+			"en":    "authorization decision - accountID: {account_id} with brandID {brand_id} access was denied",
+			"pt-BR": "decisão de autorização - o acesso da conta {account_id} à marca {brand_id} foi negado",
+		},
+	})
+}
+
 const (
 	// StoreFilter means a numeric representation of the stores filter
 	StoreFilter ResourseFilter = iota
@@ -152,7 +164,10 @@ func (a AccountAuthorizator) Authorize(ctx *routing.Context) error {
 	}
 
 	if !allowed {
-		err := errors.New("authorization decision - accountID: %s with brandID %s access was denied", accountID, brandID).WithKind(errors.KindUnauthorized).WithCode(CodeTypeAccessDenied)
+		err := errors.FromCode(CodeTypeAccessDenied, map[string]interface{}{
+			authentication.ContextKeyAccountID: accountID,
+			brand.ContextKeyBrandID:            brandID,
+		})
 		a.logger.Debug(ctx, err.Error())
 		return err
 	}
@@ -17,6 +17,18 @@ const (
 	CodeTypeMissingBrand errors.CodeType = "MISSING_BRAND"
 )
 
+func init() {
+	errors.MustRegisterCode(errors.CatalogEntry{
+		Code:        CodeTypeMissingBrand,
+		Kind:        errors.KindUnauthorized,
+		Description: "The brand header is not present on the request",
+		Messages: map[string]string{
+			"en":    "brand is not present on request headers",
+			"pt-BR": "a marca não está presente nos cabeçalhos da requisição",
+		},
+	})
+}
+
 // BrandFiller structure responsible for injecting the brand into HTTP request context.
 type BrandFiller struct {
 	logger infra.Logger
@@ -47,7 +59,7 @@ func (ua BrandFiller) Fill(ctx *routing.Context) error {
 	brandID := string(ctx.Request.Header.Peek("brand"))
 	brandID = strings.TrimSpace(brandID)
 	if len(brandID) == 0 {
-		err := errors.New("brand is not present on request headers").WithKind(errors.KindUnauthorized).WithCode(CodeTypeMissingBrand)
+		err := errors.FromCode(CodeTypeMissingBrand, nil)
 		ua.logger.Error(ctx, err)
 		return err
 	}
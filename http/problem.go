@@ -0,0 +1,174 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"io"
+	"strings"
+
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+	"github.com/valyala/fasthttp"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+// ErrorFormat defines how the Server encodes error responses.
+type ErrorFormat int
+
+const (
+	// ErrorFormatDefault encodes errors as ErrorResponse and ErrorListResponse.
+	ErrorFormatDefault ErrorFormat = iota
+	// ErrorFormatProblem encodes every error as a ProblemResponse, following RFC 7807.
+	ErrorFormatProblem
+	// ErrorFormatNegotiated encodes errors as ProblemResponse only when the client accepts
+	// MIMEProblemJSON, falling back to ErrorFormatDefault otherwise.
+	ErrorFormatNegotiated
+)
+
+// MIMEProblemJSON is the media type of RFC 7807 problem details.
+const MIMEProblemJSON = "application/problem+json"
+
+// DefaultProblemTypeBaseURI prefixes the type of ProblemResponses when no other base URI is given.
+const DefaultProblemTypeBaseURI = "urn:problem-type:"
+
+// ProblemResponse is an error response that follows RFC 7807 (https://datatracker.ietf.org/doc/html/rfc7807).
+// Its type and title are derived from the error Kind, while the error code, trace id and per-field errors are
+// carried as extension members. It implements fasthttp's HTTPError contract, like ErrorResponse does.
+type ProblemResponse struct {
+	kind     errors.KindType
+	metadata *errorMetadata
+
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	Code     errors.CodeType        `json:"code,omitempty"`
+	TraceID  string                 `json:"trace_id,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+	Errors   []errorPayload         `json:"errors,omitempty"`
+}
+
+// NewProblemResponse creates a new ProblemResponse, typed under DefaultProblemTypeBaseURI.
+func NewProblemResponse(ctx context.Context, err error) ProblemResponse {
+	return newProblemResponse(ctx, err, DefaultProblemTypeBaseURI)
+}
+
+func newProblemResponse(ctx context.Context, err error, typeBaseURI string) ProblemResponse {
+	var errorResponse ErrorResponse
+	if !stdErrors.As(err, &errorResponse) {
+		errorResponse = NewErrorResponse(ctx, err)
+	}
+
+	return ProblemResponse{
+		kind:     errorResponse.kind,
+		metadata: errorResponse.Err.metadata,
+		Type:     problemType(typeBaseURI, errorResponse.kind),
+		Title:    problemTitle(errorResponse.kind),
+		Status:   errorResponse.status,
+		Detail:   errorResponse.Err.Message,
+		Instance: requestPath(ctx),
+		Code:     errorResponse.Err.Code,
+		TraceID:  errorResponse.TraceID,
+		Details:  errorResponse.Err.details(),
+		Errors:   fieldErrorsPayload([]errorPayload{errorResponse.Err}),
+	}
+}
+
+func newProblemResponseFromList(ctx context.Context, res ErrorListResponse, typeBaseURI string) ProblemResponse {
+	problem := ProblemResponse{
+		kind:     res.kind,
+		Type:     problemType(typeBaseURI, res.kind),
+		Title:    problemTitle(res.kind),
+		Status:   res.status,
+		Instance: requestPath(ctx),
+		TraceID:  res.TraceID,
+		Errors:   res.Errs,
+	}
+
+	if len(res.Errs) == 1 {
+		problem.metadata = res.Errs[0].metadata
+		problem.Detail = res.Errs[0].Message
+		problem.Code = res.Errs[0].Code
+		problem.Details = res.Errs[0].details()
+	}
+
+	return problem
+}
+
+// filtered exposes the allowed error metadata as details and masks the sensitive data of the messages,
+// like the Server does for ErrorResponses. A nil redactor keeps the messages as they are.
+func (p ProblemResponse) filtered(allowed map[string]bool, r redactor) ProblemResponse {
+	detail := errorPayload{metadata: p.metadata, Message: p.Detail}.withDetails(allowed).redacted(r)
+	p.Detail, p.Details = detail.Message, detail.details()
+	p.Errors = redacted(withDetails(p.Errors, allowed), r)
+
+	return p
+}
+
+// Error returns the error message.
+func (p ProblemResponse) Error() string {
+	msg, _ := json.Marshal(p)
+	return string(msg)
+}
+
+// StatusCode returns the HTTP status code.
+func (p ProblemResponse) StatusCode() int {
+	return p.Status
+}
+
+// problemType builds a type URI from a Kind, e.g. INVALID_INPUT becomes <base>invalid-input.
+func problemType(baseURI string, kind errors.KindType) string {
+	return baseURI + strings.ReplaceAll(strings.ToLower(string(kind)), "_", "-")
+}
+
+// problemTitle builds a title from a Kind, e.g. INVALID_INPUT becomes Invalid Input.
+func problemTitle(kind errors.KindType) string {
+	words := strings.Split(strings.ToLower(string(kind)), "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// fieldErrorsPayload keeps only the payloads related to a field, since the others are already
+// described by the problem detail.
+func fieldErrorsPayload(payloads []errorPayload) []errorPayload {
+	var fieldPayloads []errorPayload
+	for _, payload := range payloads {
+		if payload.Field != "" {
+			fieldPayloads = append(fieldPayloads, payload)
+		}
+	}
+
+	return fieldPayloads
+}
+
+func requestPath(ctx context.Context) string {
+	if c, ok := ctx.(*routing.Context); ok && c.RequestCtx != nil {
+		return string(c.Path())
+	}
+
+	return ""
+}
+
+func acceptsProblem(ctx *routing.Context) bool {
+	return strings.Contains(string(ctx.Request.Header.Peek("Accept")), MIMEProblemJSON)
+}
+
+// problemDataWriter writes ProblemResponses as JSON, under the MIMEProblemJSON content type.
+type problemDataWriter struct{}
+
+func (w problemDataWriter) SetHeader(h *fasthttp.ResponseHeader) {
+	h.SetContentType(MIMEProblemJSON)
+}
+
+func (w problemDataWriter) Write(res io.Writer, data interface{}) error {
+	enc := json.NewEncoder(res)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(data)
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+func newProblemTestServer(format ErrorFormat) Server {
+	return newTestServer(ServerInput{
+		ErrorFormat: format,
+		Handler: func(r *routing.Router) {
+			r.Get("/error", func(ctx *routing.Context) error {
+				return errors.New("resource not found").WithKind(errors.KindNotFound).WithCode("RESOURCE_NOT_FOUND")
+			})
+
+			r.Get("/errors", func(ctx *routing.Context) error {
+				return NewErrorListResponse(ctx,
+					NewFieldError("name", CodeFieldRequired, "name is required"),
+					NewFieldError("age", CodeFieldTooSmall, "age must be at least 18"),
+				)
+			})
+		},
+	})
+}
+
+func TestProblemResponse(t *testing.T) {
+	t.Run("should encode errors as problem details when configured to", func(t *testing.T) {
+		server := newProblemTestServer(ErrorFormatProblem)
+
+		req, _ := http.NewRequest(http.MethodGet, "http://test/error", nil)
+		res, err := server.HandleRequestInMemory(req)
+		assert.NoError(t, err)
+		defer res.Body.Close()
+
+		body, err := ioutil.ReadAll(res.Body)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		assert.Equal(t, MIMEProblemJSON, res.Header.Get("Content-Type"))
+		assert.JSONEq(t, `{
+			"type": "urn:problem-type:not-found",
+			"title": "Not Found",
+			"status": 404,
+			"detail": "resource not found",
+			"instance": "/error",
+			"code": "RESOURCE_NOT_FOUND"
+		}`, string(body))
+	})
+
+	t.Run("should carry per-field errors as an extension member", func(t *testing.T) {
+		server := newProblemTestServer(ErrorFormatProblem)
+
+		status, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/errors")
+
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.JSONEq(t, `{
+			"type": "urn:problem-type:invalid-input",
+			"title": "Invalid Input",
+			"status": 400,
+			"instance": "/errors",
+			"errors": [
+				{"code": "FIELD_REQUIRED", "message": "name is required", "field": "name"},
+				{"code": "FIELD_TOO_SMALL", "message": "age must be at least 18", "field": "age"}
+			]
+		}`, string(body))
+	})
+
+	t.Run("should encode problem details only when the client accepts them", func(t *testing.T) {
+		server := newProblemTestServer(ErrorFormatNegotiated)
+
+		_, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/error")
+		assert.JSONEq(t, `{"error":{"code":"RESOURCE_NOT_FOUND","message":"resource not found"}}`, string(body))
+
+		req, _ := http.NewRequest(http.MethodGet, "http://test/error", nil)
+		req.Header.Set("Accept", MIMEProblemJSON)
+		res, err := server.HandleRequestInMemory(req)
+		assert.NoError(t, err)
+		assert.Equal(t, MIMEProblemJSON, res.Header.Get("Content-Type"))
+	})
+}
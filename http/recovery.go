@@ -0,0 +1,46 @@
+package http
+
+import (
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+// CodePanicRecovered indicates that a route handler panicked and the Server recovered from it.
+const CodePanicRecovered errors.CodeType = "PANIC_RECOVERED"
+
+func (s Server) addPanicRecovery() {
+	s.router.Use(func(ctx *routing.Context) (err error) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			// The stack trace is taken here, on the panicking goroutine, so it includes the frames that panicked.
+			panicErr := errors.New("panic: %v", recovered).
+				WithKind(errors.KindInternal).
+				WithCode(CodePanicRecovered).
+				WithStack()
+
+			// The Logger records the error on the span, so it's only recorded here when there's no Logger.
+			if s.logger != nil {
+				s.logger.Critical(ctx, panicErr)
+			} else {
+				span := spanFromContext(ctx)
+				span.RecordError(panicErr, trace.WithAttributes(attribute.String("exception.stacktrace", errors.StackTrace(panicErr))))
+				span.SetStatus(codes.Error, "panic recovered")
+			}
+
+			// The panic details are kept away from the response, since they may expose internal information.
+			err = NewErrorResponse(ctx, errors.New("an unexpected error happened while handling the request").
+				WithKind(errors.KindInternal).
+				WithCode(CodePanicRecovered))
+		}()
+
+		return ctx.Next()
+	})
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/ditointernet/go-dito/errors"
+	"github.com/ditointernet/go-dito/http/mocks"
+	"github.com/ditointernet/go-dito/log"
+)
+
+func TestPanicRecovery(t *testing.T) {
+	t.Run("should log the panic and respond with an internal ErrorResponse", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		logger := mocks.NewMockLogger(ctrl)
+		logger.EXPECT().Info(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		logger.EXPECT().Critical(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, err error) {
+			assert.Equal(t, errors.KindInternal, errors.Kind(err))
+			assert.Equal(t, CodePanicRecovered, errors.Code(err))
+			assert.Equal(t, "panic: something went wrong", err.Error())
+			assert.True(t, strings.HasPrefix(errors.StackTrace(err), "goroutine 1 [running]:"))
+			assert.Contains(t, fmt.Sprintf("%+v", err), "recovery_test.go")
+		})
+
+		server := newTestServer(ServerInput{
+			Logger: logger,
+			Handler: func(r *routing.Router) {
+				r.Get("/route", func(ctx *routing.Context) error {
+					panic("something went wrong")
+				})
+			},
+		})
+
+		status, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/route")
+
+		var res ErrorResponse
+		assert.NoError(t, json.Unmarshal(body, &res))
+		assert.Equal(t, http.StatusInternalServerError, status)
+		assert.Equal(t, CodePanicRecovered, res.Err.Code)
+		assert.NotContains(t, res.Err.Message, "something went wrong")
+	})
+
+	t.Run("should record the panic on the span once", func(t *testing.T) {
+		handler := func(r *routing.Router) {
+			r.Get("/route", func(ctx *routing.Context) error {
+				panic("something went wrong")
+			})
+		}
+
+		for desc, serverLogger := range map[string]logger{
+			"with a logger":    log.NewLogger(log.LoggerInput{Writer: ioutil.Discard}),
+			"without a logger": nil,
+		} {
+			t.Run(desc, func(t *testing.T) {
+				recorder := tracetest.NewSpanRecorder()
+				server := newTestServer(ServerInput{
+					Logger:         serverLogger,
+					Handler:        handler,
+					TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+				})
+
+				doInMemoryRequest(t, server, http.MethodGet, "http://test/route")
+
+				spans := recorder.Ended()
+				assert.Len(t, spans, 1)
+
+				var exceptions []sdktrace.Event
+				for _, event := range spans[0].Events() {
+					if event.Name == "exception" {
+						exceptions = append(exceptions, event)
+					}
+				}
+				assert.Len(t, exceptions, 1)
+
+				attrs := map[string]string{}
+				for _, attr := range exceptions[0].Attributes {
+					attrs[string(attr.Key)] = attr.Value.Emit()
+				}
+				assert.Equal(t, "panic: something went wrong", attrs["exception.message"])
+				assert.True(t, strings.HasPrefix(attrs["exception.stacktrace"], "goroutine 1 [running]:"))
+			})
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+)
+
+// contextKeyErrorKind is the key of the errors.KindType of the error returned by a route handler,
+// injected into the request context by the error handler.
+const contextKeyErrorKind string = "error_kind"
+
+// routeTemplate is returned by the handlers of the routeResolver's router. It carries the template
+// of the route which matched the request, like `/users/<id>`.
+type routeTemplate string
+
+func (t routeTemplate) Error() string {
+	return string(t)
+}
+
+// routeResolver finds out the template of the route that matches a request,
+// since fasthttp-routing doesn't expose it through the request context.
+type routeResolver struct {
+	router *routing.Router
+}
+
+// build registers every given route into the resolver. It must be called after all the routes
+// are registered into the Server router.
+func (r *routeResolver) build(routes []*routing.Route) {
+	router := routing.New()
+	router.NotFound(func(*routing.Context) error { return nil })
+
+	for _, route := range routes {
+		template := routeTemplate(route.Path())
+		router.To(route.Method(), route.Path(), func(*routing.Context) error { return template })
+	}
+
+	r.router = router
+}
+
+// resolve returns the template of the route which matches the given method and path.
+// It returns an empty string when no route matches them.
+func (r *routeResolver) resolve(method, path string) string {
+	if r.router == nil {
+		return ""
+	}
+
+	handlers, _ := r.router.Find(method, path)
+	if len(handlers) != 1 {
+		return ""
+	}
+
+	template, _ := handlers[0](nil).(routeTemplate)
+	return string(template)
+}
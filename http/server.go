@@ -6,16 +6,26 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	routing "github.com/jackwhelpton/fasthttp-routing/v2"
 	"github.com/jackwhelpton/fasthttp-routing/v2/access"
 	"github.com/jackwhelpton/fasthttp-routing/v2/content"
 	"github.com/jackwhelpton/fasthttp-routing/v2/fault"
 	"github.com/jackwhelpton/fasthttp-routing/v2/slash"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/cors"
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/fasthttpadaptor"
 	"github.com/valyala/fasthttp/fasthttputil"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	ditoErrors "github.com/ditointernet/go-dito/errors"
+	"github.com/ditointernet/go-dito/log"
 )
 
 // ServerInput encapsulates the necessary Inputs to initialize a Server
@@ -27,8 +37,63 @@ type ServerInput struct {
 	ExposedHeaders []string
 	Handler        func(*routing.Router)
 	Logger         logger
+
+	// ShutdownTimeout is how long RunContext waits for in-flight requests once its context is done.
+	// Defaults to 30 seconds.
+	ShutdownTimeout time.Duration
+	// ShutdownHooksTimeout bounds the execution of the OnShutdown hooks, which get a context of their own,
+	// so they still run when waiting for in-flight requests used up the shutdown one. Defaults to 10 seconds.
+	ShutdownHooksTimeout time.Duration
+	// OnStart hooks are executed, in the given order, right before the Server starts listening.
+	OnStart []Hook
+	// OnShutdown hooks are executed, in reverse order, after the Server stops serving requests.
+	// It's the place to flush tracers, close Pub/Sub clients and release any other resource.
+	OnShutdown []Hook
+
+	// HealthCheckers are the named checks executed by the readiness route.
+	HealthCheckers map[string]HealthChecker
+	// HealthCheckTimeout bounds the execution of all HealthCheckers. Defaults to 5 seconds.
+	HealthCheckTimeout time.Duration
+
+	// MeterProvider is used to record request metrics. Defaults to the global OpenTelemetry MeterProvider.
+	MeterProvider metric.MeterProvider
+	// MetricsGatherer, when given, is served at MetricsPath in the Prometheus exposition format.
+	MetricsGatherer prometheus.Gatherer
+
+	// TracerProvider is used to start a server span for each request. Defaults to the global OpenTelemetry
+	// TracerProvider.
+	TracerProvider trace.TracerProvider
+	// Propagator extracts the incoming trace context from request headers. Defaults to the global OpenTelemetry
+	// TextMapPropagator.
+	Propagator propagation.TextMapPropagator
+
+	// ErrorFormat defines how errors are encoded in responses. Defaults to ErrorFormatDefault.
+	ErrorFormat ErrorFormat
+	// ProblemTypeBaseURI prefixes the type of ProblemResponses. Defaults to DefaultProblemTypeBaseURI.
+	ProblemTypeBaseURI string
+	// LogLevel, when given, is served at LogLevelPath, so the log level can be read and changed at runtime.
+	// It's usually the AtomicLevel of the Logger. It requires a LogLevelGuard.
+	LogLevel *log.AtomicLevel
+	// LogLevelGuard is executed before the LogLevelPath handlers and must reject the requests that aren't allowed
+	// to manage the log level, e.g. an AccountAuthenticator Authenticate followed by an authorization check.
+	LogLevelGuard routing.Handler
+	// LogLevelHeader is the name of the request header that, holding a level name like DEBUG,
+	// overrides the log level of the request. Overriding is disabled when it's empty. It requires a LogLevelHeaderSecret.
+	LogLevelHeader string
+	// LogLevelHeaderSecret must be sent in the LogLevelSecretHeader for the LogLevelHeader to be honored,
+	// so callers can't raise the verbosity of the logs on their own.
+	LogLevelHeaderSecret string
+
+	// ErrorDetails lists the keys of the error metadata, attached with errors.CustomError WithField,
+	// that are exposed as details in error responses. Since metadata may hold sensitive data, none is exposed by default.
+	ErrorDetails []string
+	// Redactor, when given, masks sensitive data in the messages of error responses, e.g. a log.Redactor.
+	Redactor redactor
 }
 
+// Hook is a function executed at some point of the Server lifecycle, like its start or its shutdown.
+type Hook func(ctx context.Context) error
+
 // Server is an HTTPServer object that can serve HTTP requests
 // It is based on httpfast implementation
 type Server struct {
@@ -39,6 +104,31 @@ type Server struct {
 	exposedHeaders []string
 	router         *routing.Router
 	logger         logger
+
+	shutdownTimeout time.Duration
+	hooksTimeout    time.Duration
+	onStart         []Hook
+	onShutdown      []Hook
+	state           *serverState
+
+	healthCheckers     map[string]HealthChecker
+	healthCheckTimeout time.Duration
+	routes             *routeResolver
+	errorFormat        ErrorFormat
+	problemTypeBaseURI string
+	errorDetails       map[string]bool
+	redactor           redactor
+}
+
+// serverState holds everything that must be shared among the copies of a Server.
+type serverState struct {
+	httpServer   *fasthttp.Server
+	shuttingDown int32
+
+	// mux serializes the creation of the listener in Run with the start of Shutdown,
+	// so a listener is never created once the Server is shutting down.
+	mux      sync.Mutex
+	listener net.Listener
 }
 
 var defaultAllowedHeaders = []string{
@@ -53,6 +143,11 @@ var defaultExposedHeaders = []string{
 	"X-TOTAL-COUNT",
 }
 
+const (
+	defaultShutdownTimeout      = 30 * time.Second
+	defaultShutdownHooksTimeout = 10 * time.Second
+)
+
 // NewServer creates a new instance of Server
 func NewServer(in ServerInput) Server {
 	router := routing.New()
@@ -65,41 +160,189 @@ func NewServer(in ServerInput) Server {
 		in.ExposedHeaders = defaultExposedHeaders
 	}
 
+	if in.ShutdownTimeout <= 0 {
+		in.ShutdownTimeout = defaultShutdownTimeout
+	}
+
+	if in.ShutdownHooksTimeout <= 0 {
+		in.ShutdownHooksTimeout = defaultShutdownHooksTimeout
+	}
+
+	if in.HealthCheckTimeout <= 0 {
+		in.HealthCheckTimeout = defaultHealthCheckTimeout
+	}
+
+	if in.ProblemTypeBaseURI == "" {
+		in.ProblemTypeBaseURI = DefaultProblemTypeBaseURI
+	}
+
 	server := Server{
-		port:           in.Port,
-		readBufferSize: in.ReadBufferSize,
-		allowedOrigins: in.AllowedOrigins,
-		allowedHeaders: in.AllowedHeaders,
-		exposedHeaders: in.ExposedHeaders,
-		router:         router,
-		logger:         in.Logger,
+		port:            in.Port,
+		readBufferSize:  in.ReadBufferSize,
+		allowedOrigins:  in.AllowedOrigins,
+		allowedHeaders:  in.AllowedHeaders,
+		exposedHeaders:  in.ExposedHeaders,
+		router:          router,
+		logger:          in.Logger,
+		shutdownTimeout: in.ShutdownTimeout,
+		hooksTimeout:    in.ShutdownHooksTimeout,
+		onStart:         in.OnStart,
+		onShutdown:      in.OnShutdown,
+		state: &serverState{
+			httpServer: &fasthttp.Server{
+				Handler:        router.HandleRequest,
+				ReadBufferSize: in.ReadBufferSize,
+			},
+		},
+		healthCheckers:     in.HealthCheckers,
+		healthCheckTimeout: in.HealthCheckTimeout,
+		routes:             &routeResolver{},
+		errorFormat:        in.ErrorFormat,
+		problemTypeBaseURI: in.ProblemTypeBaseURI,
+		errorDetails:       map[string]bool{},
+		redactor:           in.Redactor,
+	}
+	for _, key := range in.ErrorDetails {
+		server.errorDetails[key] = true
 	}
 
+	server.addMetricsMiddleware(in.MeterProvider)
+	server.addTracingMiddleware(in.TracerProvider, in.Propagator)
 	if in.Logger != nil {
 		server.addRequestLogger()
 	}
 	router.Use(
 		slash.Remover(http.StatusMovedPermanently),
 		content.TypeNegotiator(content.JSON),
-		fault.ErrorHandler(nil, customErrorHandler),
+		fault.ErrorHandler(nil, server.handleError),
 	)
+	server.addPanicRecovery()
 
 	server.addCorsMiddleware()
 	server.addRequestIPIntoContext()
+	if in.LogLevelHeader != "" {
+		server.addLogLevelOverride(in.LogLevelHeader, in.LogLevelHeaderSecret)
+	}
+	server.addHealthRoutes()
+	if in.MetricsGatherer != nil {
+		server.addMetricsRoute(in.MetricsGatherer)
+	}
+	if in.LogLevel != nil {
+		server.addLogLevelRoute(in.LogLevel, in.LogLevelGuard)
+	}
 
 	in.Handler(router)
+	server.routes.build(router.Routes())
 
 	return server
 }
 
-// Run listen and serves HTTP requests at the Port specified in Server construction
+// Run executes the OnStart hooks, then listen and serves HTTP requests at the Port specified in Server construction.
+// It blocks until the Server fails or Shutdown is called, in which case it returns nil.
 func (s Server) Run() error {
-	server := fasthttp.Server{
-		Handler:        s.router.HandleRequest,
-		ReadBufferSize: s.readBufferSize,
+	ctx := context.Background()
+	for _, hook := range s.onStart {
+		if err := hook(ctx); err != nil {
+			return err
+		}
+	}
+
+	s.state.mux.Lock()
+	if s.IsShuttingDown() {
+		s.state.mux.Unlock()
+		return nil
+	}
+
+	ln, err := net.Listen("tcp4", fmt.Sprintf(":%d", s.port))
+	if err != nil {
+		s.state.mux.Unlock()
+		return err
+	}
+	s.state.listener = ln
+	s.state.mux.Unlock()
+
+	return s.state.httpServer.Serve(ln)
+}
+
+// RunContext works like Run, but gracefully shuts the Server down as soon as ctx is done.
+// The shutdown is bounded by the ShutdownTimeout given at Server construction.
+func (s Server) RunContext(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.Run()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
 	}
 
-	return server.ListenAndServe(fmt.Sprintf(":%d", s.port))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	if err := s.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	return <-errCh
+}
+
+// Shutdown gracefully shuts the Server down: it flips the readiness route to failing, stops accepting new
+// connections, waits for in-flight requests until ctx is done and then executes the OnShutdown hooks,
+// bounded by the ShutdownHooksTimeout given at Server construction.
+// Every hook is executed even if a previous one fails, and the first error found is returned.
+func (s Server) Shutdown(ctx context.Context) error {
+	s.state.mux.Lock()
+	atomic.StoreInt32(&s.state.shuttingDown, 1)
+	ln := s.state.listener
+	s.state.mux.Unlock()
+
+	var firstErr error
+	registerErr := func(err error) {
+		if s.logger != nil {
+			s.logger.Error(ctx, err)
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		err := s.state.httpServer.Shutdown()
+		// fasthttp only closes the listeners Serve already registered, so the listener is closed here too,
+		// making Serve return in case it was about to register it. It may already be closed, so errors are ignored.
+		if ln != nil {
+			ln.Close()
+		}
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			registerErr(err)
+		}
+	case <-ctx.Done():
+		registerErr(fmt.Errorf("timed out waiting for in-flight requests: %w", ctx.Err()))
+	}
+
+	hooksCtx, cancel := context.WithTimeout(context.Background(), s.hooksTimeout)
+	defer cancel()
+
+	for i := len(s.onShutdown) - 1; i >= 0; i-- {
+		if err := s.onShutdown[i](hooksCtx); err != nil {
+			registerErr(err)
+		}
+	}
+
+	return firstErr
+}
+
+// IsShuttingDown indicates whether Shutdown was already called on the Server.
+func (s Server) IsShuttingDown() bool {
+	return atomic.LoadInt32(&s.state.shuttingDown) == 1
 }
 
 // Handle a request in memory using the server router
@@ -150,25 +393,77 @@ func (s Server) addCorsMiddleware() {
 
 func (s Server) addRequestIPIntoContext() {
 	s.router.Use(func(ctx *routing.Context) error {
-		ctx.SetUserValue(ContextKeyRequestIPAddress, access.GetClientIP(ctx.RequestCtx))
+		ip := access.GetClientIP(ctx.RequestCtx)
+		ctx.SetUserValue(ContextKeyRequestIPAddress, ip)
+		ctx.SetUserValue(log.ContextKeyHTTPRequest, newLogHTTPRequest(ctx.RequestCtx, ip))
 		return nil
 	})
 }
 
+// newLogHTTPRequest describes the request to the Logger, so logs written while serving it carry its data.
+func newLogHTTPRequest(ctx *fasthttp.RequestCtx, ip string) *log.HTTPRequest {
+	return &log.HTTPRequest{
+		Method:      string(ctx.Method()),
+		URL:         string(ctx.RequestURI()),
+		RequestSize: int64(len(ctx.Request.Header.Header()) + len(ctx.Request.Body())),
+		UserAgent:   string(ctx.UserAgent()),
+		RemoteIP:    ip,
+		Referer:     string(ctx.Referer()),
+		Protocol:    string(ctx.Request.Header.Protocol()),
+	}
+}
+
 func (s Server) addRequestLogger() {
 	s.router.Use(access.CustomLogger(func(ctx *fasthttp.RequestCtx, elapsed float64) {
 		ip := ctx.UserValue(ContextKeyRequestIPAddress)
+		if req, ok := ctx.UserValue(log.ContextKeyHTTPRequest).(*log.HTTPRequest); ok {
+			req.Status = ctx.Response.StatusCode()
+			req.ResponseSize = int64(len(ctx.Response.Body()))
+			req.Latency = time.Duration(elapsed * float64(time.Millisecond))
+		}
+
 		req := fmt.Sprintf("%s %s %s", string(ctx.Request.Header.Method()), string(ctx.RequestURI()), string(ctx.Request.URI().Scheme()))
 		s.logger.Info(ctx, `[%v] [%.3fms] %s %d %d`, ip, elapsed, req, ctx.Response.StatusCode(), len(ctx.Response.Body()))
 	}))
 }
 
-func customErrorHandler(ctx *routing.Context, err error) error {
-	var errorResponse ErrorResponse
+func (s Server) handleError(ctx *routing.Context, err error) error {
+	var problem ProblemResponse
+	if errors.As(err, &problem) {
+		ctx.SetUserValue(contextKeyErrorKind, problem.kind)
+		ctx.SetDataWriter(problemDataWriter{})
+		return problem.filtered(s.errorDetails, s.redactor)
+	}
+
+	useProblem := s.errorFormat == ErrorFormatProblem || (s.errorFormat == ErrorFormatNegotiated && acceptsProblem(ctx))
+
+	if multiErr, ok := err.(ditoErrors.MultiError); ok {
+		err = NewErrorListResponse(ctx, multiErr)
+	}
+
 	var errorListResponse ErrorListResponse
-	if errors.As(err, &errorResponse) || errors.As(err, &errorListResponse) {
-		return err
+	if errors.As(err, &errorListResponse) {
+		ctx.SetUserValue(contextKeyErrorKind, errorListResponse.kind)
+		errorListResponse.Errs = redacted(withDetails(errorListResponse.Errs, s.errorDetails), s.redactor)
+		if !useProblem {
+			return errorListResponse
+		}
+
+		ctx.SetDataWriter(problemDataWriter{})
+		return newProblemResponseFromList(ctx, errorListResponse, s.problemTypeBaseURI)
+	}
+
+	var errorResponse ErrorResponse
+	if !errors.As(err, &errorResponse) {
+		errorResponse = NewErrorResponse(ctx, err)
+	}
+
+	ctx.SetUserValue(contextKeyErrorKind, errorResponse.kind)
+	errorResponse.Err = errorResponse.Err.withDetails(s.errorDetails).redacted(s.redactor)
+	if !useProblem {
+		return errorResponse
 	}
 
-	return NewErrorResponse(ctx, err)
+	ctx.SetDataWriter(problemDataWriter{})
+	return newProblemResponse(ctx, errorResponse, s.problemTypeBaseURI)
 }
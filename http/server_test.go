@@ -0,0 +1,102 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(in ServerInput) Server {
+	if in.Handler == nil {
+		in.Handler = func(r *routing.Router) {}
+	}
+
+	return NewServer(in)
+}
+
+func TestServerRun(t *testing.T) {
+	t.Run("should not start listening when an OnStart hook fails", func(t *testing.T) {
+		hookErr := errors.New("hook error")
+
+		server := newTestServer(ServerInput{
+			OnStart: []Hook{func(ctx context.Context) error { return hookErr }},
+		})
+
+		assert.Equal(t, hookErr, server.Run())
+	})
+}
+
+func TestServerRunContext(t *testing.T) {
+	t.Run("should execute hooks in the proper order and stop when the context is done", func(t *testing.T) {
+		calls := []string{}
+		hook := func(name string) Hook {
+			return func(ctx context.Context) error {
+				calls = append(calls, name)
+				return nil
+			}
+		}
+
+		server := newTestServer(ServerInput{
+			OnStart:    []Hook{hook("start1"), hook("start2")},
+			OnShutdown: []Hook{hook("shutdown1"), hook("shutdown2")},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		assert.NoError(t, server.RunContext(ctx))
+		assert.Equal(t, []string{"start1", "start2", "shutdown2", "shutdown1"}, calls)
+		assert.True(t, server.IsShuttingDown())
+	})
+}
+
+func TestServerShutdown(t *testing.T) {
+	t.Run("should execute every OnShutdown hook and return the first error found", func(t *testing.T) {
+		firstErr := errors.New("first error")
+		executed := 0
+
+		server := newTestServer(ServerInput{
+			OnShutdown: []Hook{
+				func(ctx context.Context) error { executed++; return errors.New("second error") },
+				func(ctx context.Context) error { executed++; return firstErr },
+			},
+		})
+
+		assert.Equal(t, firstErr, server.Shutdown(context.Background()))
+		assert.Equal(t, 2, executed)
+	})
+}
+
+func TestServerShutdownRace(t *testing.T) {
+	t.Run("should stop a Server whose Run starts right after Shutdown", func(t *testing.T) {
+		server := newTestServer(ServerInput{})
+
+		errCh := make(chan error, 1)
+		go func() { errCh <- server.Run() }()
+		assert.NoError(t, server.Shutdown(context.Background()))
+
+		select {
+		case err := <-errCh:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("Run did not return after Shutdown")
+		}
+	})
+
+	t.Run("should give OnShutdown hooks a live context when waiting for in-flight requests times out", func(t *testing.T) {
+		var hookErr error
+		server := newTestServer(ServerInput{
+			OnShutdown: []Hook{func(ctx context.Context) error { hookErr = ctx.Err(); return nil }},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_ = server.Shutdown(ctx)
+		assert.NoError(t, hookErr)
+	})
+}
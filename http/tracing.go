@@ -0,0 +1,123 @@
+package http
+
+import (
+	"context"
+	"fmt"
+
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ditointernet/go-dito/errors"
+	"github.com/ditointernet/go-dito/log"
+)
+
+// ContextKeySpan is the key of the server span injected into the request context.
+// It's the same key the log package looks up, so logs written within a request are correlated with its span.
+const ContextKeySpan string = log.ContextKeySpan
+
+// Span attribute keys recorded by the Server, besides the metric ones.
+const (
+	AttributeKeyTarget    = attribute.Key("http.target")
+	AttributeKeyUserAgent = attribute.Key("http.user_agent")
+)
+
+// requestHeaderCarrier adapts fasthttp request headers to the propagation.TextMapCarrier contract.
+type requestHeaderCarrier struct {
+	header *fasthttp.RequestHeader
+}
+
+func (c requestHeaderCarrier) Get(key string) string {
+	return string(c.header.Peek(key))
+}
+
+func (c requestHeaderCarrier) Set(key, value string) {
+	c.header.Set(key, value)
+}
+
+func (c requestHeaderCarrier) Keys() []string {
+	keys := []string{}
+	c.header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+
+	return keys
+}
+
+func (s Server) addTracingMiddleware(provider trace.TracerProvider, propagator propagation.TextMapPropagator) {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+
+	tracer := provider.Tracer(instrumentationName)
+
+	s.router.Use(func(ctx *routing.Context) error {
+		method := string(ctx.Method())
+		route := s.routes.resolve(method, string(ctx.Path()))
+
+		spanName := fmt.Sprintf("HTTP %s", method)
+		if route != "" {
+			spanName = fmt.Sprintf("%s %s", method, route)
+		}
+
+		attrs := []attribute.KeyValue{
+			AttributeKeyMethod.String(method),
+			AttributeKeyTarget.String(string(ctx.RequestURI())),
+			AttributeKeyUserAgent.String(string(ctx.UserAgent())),
+		}
+		if route != "" {
+			attrs = append(attrs, AttributeKeyRoute.String(route))
+		}
+
+		parent := propagator.Extract(context.Background(), requestHeaderCarrier{header: &ctx.Request.Header})
+		_, span := tracer.Start(parent, spanName, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+		defer span.End()
+
+		ctx.SetUserValue(ContextKeySpan, span)
+
+		err := ctx.Next()
+
+		status := ctx.Response.StatusCode()
+		if httpErr, ok := err.(routing.HTTPError); ok {
+			status = httpErr.StatusCode()
+		}
+
+		span.SetAttributes(AttributeKeyStatusCode.Int(status))
+		if kind, ok := ctx.UserValue(contextKeyErrorKind).(errors.KindType); ok {
+			span.SetAttributes(AttributeKeyErrorKind.String(string(kind)))
+		}
+		if status >= 500 {
+			span.SetStatus(codes.Error, fasthttp.StatusMessage(status))
+		}
+
+		return err
+	})
+}
+
+// ContextWithSpan returns a copy of ctx that carries the server span of the request in a way that is understood by
+// any OpenTelemetry instrumentation, like the one used by Client. It should be used whenever the request context is
+// handed to code that isn't aware of ContextKeySpan.
+func ContextWithSpan(ctx context.Context) context.Context {
+	return trace.ContextWithSpan(ctx, spanFromContext(ctx))
+}
+
+func spanFromContext(ctx context.Context) trace.Span {
+	span := trace.SpanFromContext(ctx)
+	if span.SpanContext().IsValid() {
+		return span
+	}
+
+	if s, ok := ctx.Value(ContextKeySpan).(trace.Span); ok {
+		return s
+	}
+
+	return span
+}
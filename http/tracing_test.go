@@ -0,0 +1,78 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	traceparent := "00-" + traceID + "-00f067aa0ba902b7-01"
+
+	t.Run("should start a server span child of the incoming trace and expose its trace id on errors", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+
+		server := newTestServer(ServerInput{
+			TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+			Propagator:     propagation.TraceContext{},
+			Handler: func(r *routing.Router) {
+				r.Get("/users/<id>", func(ctx *routing.Context) error {
+					return errors.New("random error").WithKind(errors.KindInternal)
+				})
+			},
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "http://test/users/123", nil)
+		req.Header.Set("traceparent", traceparent)
+		res, err := server.HandleRequestInMemory(req)
+		assert.NoError(t, err)
+		defer res.Body.Close()
+
+		var body ErrorResponse
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, traceID, body.TraceID)
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "GET /users/<id>", spans[0].Name())
+		assert.Equal(t, traceID, spans[0].SpanContext().TraceID().String())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Contains(t, spans[0].Attributes(), AttributeKeyStatusCode.Int(http.StatusInternalServerError))
+		assert.Contains(t, spans[0].Attributes(), attribute.String("error.kind", string(errors.KindInternal)))
+	})
+
+	t.Run("should make the server span available to the handlers", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		var handlerTraceID string
+
+		server := newTestServer(ServerInput{
+			TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+			Propagator:     propagation.TraceContext{},
+			Handler: func(r *routing.Router) {
+				r.Get("/route", func(ctx *routing.Context) error {
+					handlerTraceID = getTraceID(spanFromContext(ContextWithSpan(ctx)))
+					return nil
+				})
+			},
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "http://test/route", nil)
+		req.Header.Set("traceparent", traceparent)
+		_, err := server.HandleRequestInMemory(req)
+		assert.NoError(t, err)
+
+		assert.Equal(t, traceID, handlerTraceID)
+		assert.Equal(t, codes.Unset, recorder.Ended()[0].Status().Code)
+	})
+}
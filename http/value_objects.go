@@ -3,6 +3,7 @@ package http
 import (
 	"context"
 	"encoding/json"
+	stdErrors "errors"
 	"fmt"
 
 	"go.opentelemetry.io/otel/trace"
@@ -18,12 +19,116 @@ type logger interface {
 	Critical(ctx context.Context, err error)
 }
 
+type redactor interface {
+	Redact(s string) string
+}
+
 // ContextKeyRequestIPAddress is the key of RequestIP information injected into the request context
 const ContextKeyRequestIPAddress string = "request_ip"
 
 type errorPayload struct {
+	// metadata is kept behind a pointer, so ErrorResponse stays comparable, like errors usually are.
+	metadata *errorMetadata
+
 	Code    errors.CodeType `json:"code,omitempty"`
 	Message string          `json:"message"`
+	Field   string          `json:"field,omitempty"`
+}
+
+// errorMetadata holds the metadata of an error and the part of it exposed as details.
+type errorMetadata struct {
+	fields  map[string]interface{}
+	details map[string]interface{}
+}
+
+func newErrorPayload(err error, locales []string) errorPayload {
+	payload := errorPayload{
+		Code:    errors.Code(err),
+		Message: localizedMessage(err, locales),
+	}
+
+	if fields := errors.Fields(err); len(fields) > 0 {
+		payload.metadata = &errorMetadata{fields: fields}
+	}
+
+	var fieldErr FieldError
+	if stdErrors.As(err, &fieldErr) {
+		payload.Field = fieldErr.Field
+	}
+
+	return payload
+}
+
+// withDetails exposes, as details, the error metadata whose keys are allowed.
+func (p errorPayload) withDetails(allowed map[string]bool) errorPayload {
+	if p.metadata == nil {
+		return p
+	}
+
+	var details map[string]interface{}
+	for key, value := range p.metadata.fields {
+		if !allowed[key] {
+			continue
+		}
+
+		if details == nil {
+			details = map[string]interface{}{}
+		}
+		details[key] = value
+	}
+
+	p.metadata = &errorMetadata{fields: p.metadata.fields, details: details}
+	return p
+}
+
+// details returns the error metadata exposed by withDetails.
+func (p errorPayload) details() map[string]interface{} {
+	if p.metadata == nil {
+		return nil
+	}
+
+	return p.metadata.details
+}
+
+// MarshalJSON encodes the payload along with its details.
+func (p errorPayload) MarshalJSON() ([]byte, error) {
+	type payload errorPayload
+
+	return json.Marshal(struct {
+		payload
+		Details map[string]interface{} `json:"details,omitempty"`
+	}{payload(p), p.details()})
+}
+
+// redacted masks the sensitive data of the payload message. A nil redactor keeps it as is.
+func (p errorPayload) redacted(r redactor) errorPayload {
+	if r != nil {
+		p.Message = r.Redact(p.Message)
+	}
+
+	return p
+}
+
+func withDetails(payloads []errorPayload, allowed map[string]bool) []errorPayload {
+	detailed := make([]errorPayload, len(payloads))
+	for i, payload := range payloads {
+		detailed[i] = payload.withDetails(allowed)
+	}
+
+	return detailed
+}
+
+func redacted(payloads []errorPayload, r redactor) []errorPayload {
+	if r == nil {
+		return payloads
+	}
+
+	redacted := make([]errorPayload, len(payloads))
+	for i, payload := range payloads {
+		redacted[i] = payload.redacted(r)
+	}
+
+	return redacted
 }
 
 // ErrorResponse defines how errors should be presented to clients of HTTPServer.
@@ -31,20 +136,22 @@ type errorPayload struct {
 // so it keeps its error handling behaviour.
 type ErrorResponse struct {
 	status int
+	kind   errors.KindType
 
 	TraceID string       `json:"trace_id,omitempty"`
 	Err     errorPayload `json:"error"`
 }
 
 // NewErrorResponse creates a new ErrorResponse object.
+// The metadata attached to err is exposed as details only for the keys allowed by the Server ErrorDetails.
+// When the code of err is registered at errors.DefaultCatalog, its message is localized according to the
+// request Accept-Language header, and its registered HTTP status is used.
 func NewErrorResponse(ctx context.Context, err error) ErrorResponse {
 	return ErrorResponse{
-		TraceID: getTraceID(trace.SpanFromContext(ctx)),
-		status:  kindToHTTPStatusCode(errors.Kind(err)),
-		Err: errorPayload{
-			Code:    errors.Code(err),
-			Message: err.Error(),
-		},
+		TraceID: getTraceID(spanFromContext(ctx)),
+		status:  errorStatusCode(err),
+		kind:    errors.Kind(err),
+		Err:     newErrorPayload(err, acceptedLanguages(ctx)),
 	}
 }
 
@@ -64,30 +171,43 @@ func (e ErrorResponse) StatusCode() int {
 // so it keeps its error handling behaviour.
 type ErrorListResponse struct {
 	status int
+	kind   errors.KindType
 
 	TraceID string         `json:"trace_id,omitempty"`
 	Errs    []errorPayload `json:"errors"`
 }
 
-// NewErrorListResponse creates a new ErrorListResponse object.
+// NewErrorListResponse creates a new ErrorListResponse object. Messages are localized like the ones of NewErrorResponse.
+// When a single errors.MultiError is given, each of its errors is listed, and its aggregate kind defines the status code.
 func NewErrorListResponse(ctx context.Context, errs ...error) ErrorListResponse {
+	kind := errors.KindUnexpected
+	if len(errs) > 0 {
+		kind = errors.Kind(errs[0])
+	}
+
+	if len(errs) == 1 {
+		if multiErr, ok := errs[0].(errors.MultiError); ok {
+			errs = multiErr.Errors()
+		}
+	}
+
 	if len(errs) == 0 {
 		return ErrorListResponse{
 			status: 500,
+			kind:   errors.KindUnexpected,
 		}
 	}
 
+	locales := acceptedLanguages(ctx)
 	errsPayload := []errorPayload{}
 	for _, err := range errs {
-		errsPayload = append(errsPayload, errorPayload{
-			Code:    errors.Code(err),
-			Message: err.Error(),
-		})
+		errsPayload = append(errsPayload, newErrorPayload(err, locales))
 	}
 
 	return ErrorListResponse{
-		TraceID: getTraceID(trace.SpanFromContext(ctx)),
-		status:  kindToHTTPStatusCode(errors.Kind(errs[0])),
+		TraceID: getTraceID(spanFromContext(ctx)),
+		status:  kindToHTTPStatusCode(kind),
+		kind:    kind,
 		Errs:    errsPayload,
 	}
 }
@@ -119,24 +239,7 @@ func getTraceID(span trace.Span) string {
 }
 
 func kindToHTTPStatusCode(kind errors.KindType) int {
-	switch kind {
-	case errors.KindInvalidInput:
-		return 400
-	case errors.KindUnauthenticated:
-		return 401
-	case errors.KindUnauthorized:
-		return 403
-	case errors.KindNotFound:
-		return 404
-	case errors.KindConflict:
-		return 409
-	case errors.KindUnexpected:
-		return 500
-	case errors.KindInternal:
-		return 500
-	default:
-		return 500
-	}
+	return errors.HTTPStatusOfKind(kind)
 }
 
 // MessageResponse is a generic message that should be sent to a client of HTTP Server.
@@ -185,3 +288,18 @@ type HTTPResult struct {
 	StatusCode int
 	Response   []byte
 }
+
+// CodeUnsuccessfulRequest indicates that an HTTP request was answered with a non successful status code
+const CodeUnsuccessfulRequest errors.CodeType = "UNSUCCESSFUL_REQUEST"
+
+// Err converts an unsuccessful HTTPResult into a CustomError whose Kind is the one registered for its StatusCode.
+// It returns nil when the StatusCode doesn't represent an error.
+func (r HTTPResult) Err() error {
+	if r.StatusCode < 400 {
+		return nil
+	}
+
+	return errors.New("request was not successful. Received status: %d", r.StatusCode).
+		WithKind(errors.KindFromHTTPStatus(r.StatusCode)).
+		WithCode(CodeUnsuccessfulRequest)
+}
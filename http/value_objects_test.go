@@ -0,0 +1,234 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"testing"
+
+	routing "github.com/jackwhelpton/fasthttp-routing/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ditointernet/go-dito/errors"
+	"github.com/ditointernet/go-dito/log"
+)
+
+func TestNewErrorResponse(t *testing.T) {
+	t.Run("should use the HTTP status registered for the error kind", func(t *testing.T) {
+		var kindPaymentRequired errors.KindType = "PAYMENT_REQUIRED"
+		errors.RegisterKind(kindPaymentRequired, errors.KindInfo{HTTPStatus: 402})
+
+		res := NewErrorResponse(context.Background(), errors.New("random error").WithKind(kindPaymentRequired))
+
+		assert.Equal(t, 402, res.StatusCode())
+	})
+
+	t.Run("should map the built-in kinds to their HTTP status", func(t *testing.T) {
+		res := NewErrorResponse(context.Background(), errors.New("random error").WithKind(errors.KindRateLimited))
+
+		assert.Equal(t, 429, res.StatusCode())
+	})
+
+	t.Run("should be comparable even when the error has metadata", func(t *testing.T) {
+		err := errors.New("random error").WithField("entity_id", "123")
+		res := NewErrorResponse(context.Background(), err)
+		same := res
+
+		assert.NotPanics(t, func() {
+			assert.True(t, res == same)
+			assert.False(t, res == NewErrorResponse(context.Background(), err))
+		})
+	})
+}
+
+func TestHTTPResultErr(t *testing.T) {
+	t.Run("should return nil when the request was successful", func(t *testing.T) {
+		assert.NoError(t, HTTPResult{StatusCode: 204}.Err())
+	})
+
+	t.Run("should return an error whose kind matches the status code", func(t *testing.T) {
+		err := HTTPResult{StatusCode: 503}.Err()
+
+		assert.EqualError(t, err, "request was not successful. Received status: 503")
+		assert.Equal(t, errors.KindUnavailable, errors.Kind(err))
+		assert.Equal(t, CodeUnsuccessfulRequest, errors.Code(err))
+		assert.True(t, errors.IsRetryable(err))
+	})
+}
+
+func TestErrorDetails(t *testing.T) {
+	handler := func(r *routing.Router) {
+		r.Get("/error", func(ctx *routing.Context) error {
+			return errors.New("resource not found").
+				WithKind(errors.KindNotFound).
+				WithField("entity_id", "123").
+				WithField("user_email", "john@dito.com")
+		})
+	}
+
+	t.Run("should not expose any metadata by default", func(t *testing.T) {
+		server := newTestServer(ServerInput{Handler: handler})
+
+		_, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/error")
+		assert.JSONEq(t, `{"error":{"code":"UNKNOWN","message":"resource not found"}}`, string(body))
+	})
+
+	t.Run("should expose only the allowed metadata as details", func(t *testing.T) {
+		server := newTestServer(ServerInput{Handler: handler, ErrorDetails: []string{"entity_id"}})
+
+		status, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/error")
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.JSONEq(t, `{"error":{"code":"UNKNOWN","message":"resource not found","details":{"entity_id":"123"}}}`, string(body))
+	})
+
+	t.Run("should expose the allowed metadata as details of problem responses", func(t *testing.T) {
+		server := newTestServer(ServerInput{Handler: handler, ErrorDetails: []string{"entity_id"}, ErrorFormat: ErrorFormatProblem})
+
+		_, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/error")
+		assert.JSONEq(t, `{
+			"type": "urn:problem-type:not-found",
+			"title": "Not Found",
+			"status": 404,
+			"detail": "resource not found",
+			"instance": "/error",
+			"code": "UNKNOWN",
+			"details": {"entity_id": "123"}
+		}`, string(body))
+	})
+}
+
+func TestErrorRedaction(t *testing.T) {
+	handler := func(r *routing.Router) {
+		r.Get("/error", func(ctx *routing.Context) error {
+			return errors.New("customer john@dito.com not found").WithKind(errors.KindNotFound)
+		})
+	}
+
+	t.Run("should keep error messages as they are by default", func(t *testing.T) {
+		server := newTestServer(ServerInput{Handler: handler})
+
+		_, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/error")
+		assert.JSONEq(t, `{"error":{"code":"UNKNOWN","message":"customer john@dito.com not found"}}`, string(body))
+	})
+
+	t.Run("should mask sensitive data of error messages", func(t *testing.T) {
+		redactor := log.NewRedactor(log.RedactorInput{Patterns: []*regexp.Regexp{log.PatternEmail}})
+		server := newTestServer(ServerInput{Handler: handler, Redactor: redactor})
+
+		_, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/error")
+		assert.JSONEq(t, `{"error":{"code":"UNKNOWN","message":"customer [REDACTED] not found"}}`, string(body))
+	})
+
+	t.Run("should filter the ProblemResponses returned by handlers as well", func(t *testing.T) {
+		redactor := log.NewRedactor(log.RedactorInput{Patterns: []*regexp.Regexp{log.PatternEmail}})
+		server := newTestServer(ServerInput{
+			Redactor:     redactor,
+			ErrorDetails: []string{"customer_id"},
+			Handler: func(r *routing.Router) {
+				r.Get("/problem", func(ctx *routing.Context) error {
+					return NewProblemResponse(ctx, errors.New("customer john@dito.com not found").
+						WithKind(errors.KindNotFound).
+						WithField("customer_id", "123").
+						WithField("email", "john@dito.com"))
+				})
+			},
+		})
+
+		_, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/problem")
+		assert.JSONEq(t, `{
+			"type":"urn:problem-type:not-found",
+			"title":"Not Found",
+			"status":404,
+			"detail":"customer [REDACTED] not found",
+			"instance":"/problem",
+			"code":"UNKNOWN",
+			"details":{"customer_id":"123"}
+		}`, string(body))
+	})
+}
+
+func TestMultiErrorResponse(t *testing.T) {
+	t.Run("should render every error of a MultiError, with the status of its aggregate kind", func(t *testing.T) {
+		server := newTestServer(ServerInput{
+			Handler: func(r *routing.Router) {
+				r.Get("/batch", func(ctx *routing.Context) error {
+					return errors.NewMultiError(
+						NewFieldError("name", CodeFieldRequired, "name is required"),
+						errors.New("resource not found").WithKind(errors.KindNotFound).WithCode("RESOURCE_NOT_FOUND"),
+					)
+				})
+			},
+		})
+
+		status, body := doInMemoryRequest(t, server, http.MethodGet, "http://test/batch")
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.JSONEq(t, `{"errors":[
+			{"code":"FIELD_REQUIRED","message":"name is required","field":"name"},
+			{"code":"RESOURCE_NOT_FOUND","message":"resource not found"}
+		]}`, string(body))
+	})
+}
+
+func TestLocalizedErrorResponse(t *testing.T) {
+	errors.MustRegisterCode(errors.CatalogEntry{
+		Code:       "TEST_BRAND_NOT_FOUND",
+		Kind:       errors.KindNotFound,
+		HTTPStatus: http.StatusGone,
+		Messages: map[string]string{
+			"en":    "brand {brand_id} not found",
+			"pt-BR": "marca {brand_id} não encontrada",
+		},
+	})
+
+	server := newTestServer(ServerInput{
+		Handler: func(r *routing.Router) {
+			r.Get("/error", func(ctx *routing.Context) error {
+				return errors.FromCode("TEST_BRAND_NOT_FOUND", map[string]interface{}{"brand_id": "dito"})
+			})
+		},
+	})
+
+	tt := []struct {
+		desc           string
+		acceptLanguage string
+		expectedBody   string
+	}{
+		{
+			desc:         "should use the default locale when no language is accepted",
+			expectedBody: `{"error":{"code":"TEST_BRAND_NOT_FOUND","message":"brand dito not found"}}`,
+		},
+		{
+			desc:           "should use the accepted language with the highest quality",
+			acceptLanguage: "en;q=0.5, pt-BR;q=0.9, fr",
+			expectedBody:   `{"error":{"code":"TEST_BRAND_NOT_FOUND","message":"marca dito não encontrada"}}`,
+		},
+		{
+			desc:           "should read the quality of params with spaces around them",
+			acceptLanguage: "en;q=0.5, pt; q=0.9",
+			expectedBody:   `{"error":{"code":"TEST_BRAND_NOT_FOUND","message":"marca dito não encontrada"}}`,
+		},
+		{
+			desc:           "should match the accepted language by its language",
+			acceptLanguage: "pt",
+			expectedBody:   `{"error":{"code":"TEST_BRAND_NOT_FOUND","message":"marca dito não encontrada"}}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "http://test/error", nil)
+			req.Header.Set("Accept-Language", tc.acceptLanguage)
+
+			res, err := server.HandleRequestInMemory(req)
+			assert.NoError(t, err)
+			defer res.Body.Close()
+
+			body, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+
+			assert.Equal(t, http.StatusGone, res.StatusCode)
+			assert.JSONEq(t, tc.expectedBody, string(body))
+		})
+	}
+}
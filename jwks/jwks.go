@@ -11,6 +11,9 @@ import (
 	client "github.com/ditointernet/go-dito/http"
 )
 
+// CodeTypeCertsNotLoaded indicates that no certificate was loaded into the Client yet
+const CodeTypeCertsNotLoaded errors.CodeType = "JWKS_CERTS_NOT_LOADED"
+
 // Client is the structure responsible for handling JWKS certificates
 type Client struct {
 	jwksURI              string
@@ -19,6 +22,8 @@ type Client struct {
 	lastRenewTime        time.Time
 	renewMinuteThreshold int
 	mux                  sync.Mutex
+	// certsMux guards only certs, so readers are not blocked while mux is held during a renewal
+	certsMux sync.RWMutex
 }
 
 // NewClient constructs a new JWKS instance
@@ -60,7 +65,7 @@ func (c *Client) GetCerts(ctx context.Context) error {
 		return err
 	}
 
-	c.certs = certs
+	c.setCerts(certs)
 
 	return nil
 }
@@ -74,16 +79,35 @@ func (c *Client) RenewCerts(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
-		c.certs = certs
+		c.setCerts(certs)
 	}
 	return nil
 }
 
 // Certs return a list of valid certs
 func (c *Client) Certs() map[string]string {
+	c.certsMux.RLock()
+	defer c.certsMux.RUnlock()
+
 	return c.certs
 }
 
+func (c *Client) setCerts(certs map[string]string) {
+	c.certsMux.Lock()
+	defer c.certsMux.Unlock()
+
+	c.certs = certs
+}
+
+// HealthCheck reports whether the Client has already loaded any certificate.
+func (c *Client) HealthCheck(ctx context.Context) error {
+	if len(c.Certs()) == 0 {
+		return errors.New("no JWKS certificate was loaded").WithKind(errors.KindInternal).WithCode(CodeTypeCertsNotLoaded)
+	}
+
+	return nil
+}
+
 type jwk struct {
 	KeyID           string   `json:"kid"`
 	X509Certificate []string `json:"x5c"`
@@ -161,3 +161,42 @@ func getBody() []byte {
 
 	return body
 }
+
+func TestClient_HealthCheck(t *testing.T) {
+	t.Run("should return an error when no certificate was loaded", func(t *testing.T) {
+		c := &Client{}
+
+		assert.EqualError(t, c.HealthCheck(context.Background()), "no JWKS certificate was loaded")
+	})
+
+	t.Run("should return no error when certificates were loaded", func(t *testing.T) {
+		c := &Client{certs: map[string]string{"1": "cert"}}
+
+		assert.NoError(t, c.HealthCheck(context.Background()))
+	})
+
+	t.Run("should not wait for an ongoing certificates renewal", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		httpMock := mocks.NewMockHTTPClient(ctrl)
+		c := &Client{jwksURI: "jwksURI", http: httpMock, certs: map[string]string{"1": "cert"}}
+
+		fetching, release := make(chan struct{}), make(chan struct{})
+		httpMock.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req http.HTTPRequest) (http.HTTPResult, error) {
+			close(fetching)
+			<-release
+			return http.HTTPResult{StatusCode: 200, Response: getBody()}, nil
+		})
+
+		renewed := make(chan error, 1)
+		go func() { renewed <- c.RenewCerts(context.Background()) }()
+		<-fetching
+
+		assert.NoError(t, c.HealthCheck(context.Background()))
+
+		close(release)
+		assert.NoError(t, <-renewed)
+		assert.Len(t, c.Certs(), 2)
+	})
+}
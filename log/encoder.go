@@ -0,0 +1,233 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Entry is a single log record, as handed to an Encoder
+type Entry struct {
+	Time        time.Time
+	Level       Level
+	Message     string
+	Attributes  map[LogAttribute]interface{}
+	SpanContext trace.SpanContext
+	// StackTrace is the goroutine dump captured along with an error, if any
+	StackTrace string
+	// PC is the program counter of the code that wrote the log, if known
+	PC uintptr
+	// HTTPRequest is the request being served when the log was written, if any
+	HTTPRequest *HTTPRequest
+}
+
+// Encoder serializes log Entries. Each encoded Entry must end with a line break
+type Encoder interface {
+	Encode(entry Entry) ([]byte, error)
+}
+
+// GCPEncoder encodes Entries as JSON, following the structured logging format of Google Cloud Logging,
+// so they are correlated with traces and picked up by Cloud Error Reporting. It's the default Encoder.
+type GCPEncoder struct {
+	// ProjectID is the Google Cloud project that hosts the traces. Defaults to new-dito.
+	ProjectID string
+	// FullSpec enables the sourceLocation, httpRequest and labels fields
+	// (https://cloud.google.com/logging/docs/structured-logging#special-payload-fields).
+	FullSpec bool
+	// Labels are emitted along with every log when FullSpec is enabled
+	Labels map[string]string
+}
+
+type logData struct {
+	Type           string                       `json:"@type,omitempty"`
+	Trace          string                       `json:"logging.googleapis.com/trace,omitempty"`
+	SpanID         string                       `json:"logging.googleapis.com/spanId,omitempty"`
+	TraceSampled   *bool                        `json:"logging.googleapis.com/trace_sampled,omitempty"`
+	SourceLocation *sourceLocationData          `json:"logging.googleapis.com/sourceLocation,omitempty"`
+	Labels         map[string]string            `json:"logging.googleapis.com/labels,omitempty"`
+	HTTPRequest    *httpRequestData             `json:"httpRequest,omitempty"`
+	Timestamp      string                       `json:"time"`
+	Level          string                       `json:"severity"`
+	Message        string                       `json:"message"`
+	Attributes     map[LogAttribute]interface{} `json:"attributes,omitempty"`
+	StackTrace     string                       `json:"stack_trace,omitempty"`
+}
+
+// Encode encodes the Entry as a Cloud Logging JSON line
+func (e GCPEncoder) Encode(entry Entry) ([]byte, error) {
+	data := logData{
+		Trace:        getTrace(e.projectID(), entry.SpanContext),
+		SpanID:       getSpanID(entry.SpanContext),
+		TraceSampled: getIsTraceSampled(entry.SpanContext),
+		Timestamp:    entry.Time.Format(time.RFC3339),
+		Level:        entry.Level.String(),
+		Message:      entry.Message,
+		Attributes:   entry.Attributes,
+	}
+
+	if e.FullSpec {
+		data.SourceLocation = getSourceLocation(entry.PC)
+		data.HTTPRequest = newHTTPRequestData(entry.HTTPRequest)
+		data.Labels = e.Labels
+	}
+
+	if entry.StackTrace != "" {
+		data.Type = ReportedErrorEventType
+		data.StackTrace = fmt.Sprintf("%s\n\n%s", entry.Message, entry.StackTrace)
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+func (e GCPEncoder) projectID() string {
+	if e.ProjectID == "" {
+		return defaultProjectID
+	}
+
+	return e.ProjectID
+}
+
+func getTrace(projectID string, sc trace.SpanContext) string {
+	if !sc.TraceID().IsValid() {
+		return ""
+	}
+
+	return fmt.Sprintf("projects/%s/traces/%s", projectID, sc.TraceID().String())
+}
+
+func getSourceLocation(pc uintptr) *sourceLocationData {
+	if pc == 0 {
+		return nil
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.File == "" {
+		return nil
+	}
+
+	return &sourceLocationData{File: frame.File, Line: strconv.Itoa(frame.Line), Function: frame.Function}
+}
+
+func getSpanID(sc trace.SpanContext) string {
+	if !sc.TraceID().IsValid() {
+		return ""
+	}
+
+	return sc.SpanID().String()
+}
+
+func getIsTraceSampled(sc trace.SpanContext) *bool {
+	if !sc.TraceID().IsValid() {
+		return nil
+	}
+
+	isSampled := sc.IsSampled()
+	return &isSampled
+}
+
+// ConsoleEncoder encodes Entries as human readable lines, colored by level, suited for local development
+type ConsoleEncoder struct {
+	// NoColor disables the ANSI colors, e.g. when the output isn't a terminal
+	NoColor bool
+}
+
+var levelColors = map[Level]int{
+	LevelCritical: 35,
+	LevelError:    31,
+	LevelWarning:  33,
+	LevelInfo:     34,
+	LevelDebug:    90,
+}
+
+// Encode encodes the Entry as a console line, followed by its stack trace, if any
+func (e ConsoleEncoder) Encode(entry Entry) ([]byte, error) {
+	var b strings.Builder
+
+	level := fmt.Sprintf("%-8s", entry.Level.String())
+	if !e.NoColor {
+		level = fmt.Sprintf("\x1b[%dm%s\x1b[0m", levelColors[entry.Level], level)
+	}
+
+	fmt.Fprintf(&b, "%s %s %s", entry.Time.Format(time.RFC3339), level, entry.Message)
+
+	for _, kv := range sortedAttributes(entry) {
+		fmt.Fprintf(&b, " %s=%s", kv.key, formatValue(kv.value))
+	}
+
+	b.WriteByte('\n')
+	if entry.StackTrace != "" {
+		b.WriteString(entry.StackTrace)
+		b.WriteByte('\n')
+	}
+
+	return []byte(b.String()), nil
+}
+
+// LogfmtEncoder encodes Entries as logfmt lines (https://brandur.org/logfmt)
+type LogfmtEncoder struct{}
+
+// Encode encodes the Entry as a logfmt line
+func (e LogfmtEncoder) Encode(entry Entry) ([]byte, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "time=%s level=%s msg=%s", entry.Time.Format(time.RFC3339), entry.Level.String(), formatValue(entry.Message))
+	for _, kv := range sortedAttributes(entry) {
+		fmt.Fprintf(&b, " %s=%s", kv.key, formatValue(kv.value))
+	}
+
+	if entry.StackTrace != "" {
+		fmt.Fprintf(&b, " stack_trace=%s", formatValue(entry.StackTrace))
+	}
+
+	b.WriteByte('\n')
+	return []byte(b.String()), nil
+}
+
+type keyValue struct {
+	key   string
+	value interface{}
+}
+
+// sortedAttributes returns the trace correlation fields followed by the Entry attributes, sorted by key
+func sortedAttributes(entry Entry) []keyValue {
+	var kvs []keyValue
+	if entry.SpanContext.TraceID().IsValid() {
+		kvs = append(kvs,
+			keyValue{key: "trace_id", value: entry.SpanContext.TraceID().String()},
+			keyValue{key: "span_id", value: entry.SpanContext.SpanID().String()},
+		)
+	}
+
+	keys := make([]string, 0, len(entry.Attributes))
+	for key := range entry.Attributes {
+		keys = append(keys, string(key))
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		kvs = append(kvs, keyValue{key: key, value: entry.Attributes[LogAttribute(key)]})
+	}
+
+	return kvs
+}
+
+// formatValue formats a value to be printed after a key=, quoting it when needed
+func formatValue(value interface{}) string {
+	s := fmt.Sprint(value)
+	if s == "" || strings.ContainsAny(s, " =\"\t\r\n") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
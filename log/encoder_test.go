@@ -0,0 +1,194 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEncoders(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+
+	entry := Entry{
+		Time:       mockedTimmer()(),
+		Level:      LevelWarning,
+		Message:    "random message",
+		Attributes: map[LogAttribute]interface{}{"brand": "dito", "query": "a=b"},
+		SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+		}),
+	}
+
+	tt := []struct {
+		desc        string
+		encoder     Encoder
+		expectedLog string
+	}{
+		{
+			desc:        "should encode entries as Cloud Logging JSON",
+			encoder:     GCPEncoder{},
+			expectedLog: `{"logging.googleapis.com/trace":"projects/new-dito/traces/4bf92f3577b34da6a3ce929d0e0e4736","logging.googleapis.com/spanId":"00f067aa0ba902b7","logging.googleapis.com/trace_sampled":true,"time":"2020-12-01T12:00:00Z","severity":"WARNING","message":"random message","attributes":{"brand":"dito","query":"a=b"}}` + "\n",
+		},
+		{
+			desc:        "should link traces of the given project",
+			encoder:     GCPEncoder{ProjectID: "other-project"},
+			expectedLog: `{"logging.googleapis.com/trace":"projects/other-project/traces/4bf92f3577b34da6a3ce929d0e0e4736","logging.googleapis.com/spanId":"00f067aa0ba902b7","logging.googleapis.com/trace_sampled":true,"time":"2020-12-01T12:00:00Z","severity":"WARNING","message":"random message","attributes":{"brand":"dito","query":"a=b"}}` + "\n",
+		},
+		{
+			desc:        "should encode entries as colored console lines",
+			encoder:     ConsoleEncoder{},
+			expectedLog: "2020-12-01T12:00:00Z \x1b[33mWARNING \x1b[0m random message trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7 brand=dito query=\"a=b\"\n",
+		},
+		{
+			desc:        "should encode entries as console lines without colors",
+			encoder:     ConsoleEncoder{NoColor: true},
+			expectedLog: "2020-12-01T12:00:00Z WARNING  random message trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7 brand=dito query=\"a=b\"\n",
+		},
+		{
+			desc:        "should encode entries as logfmt lines",
+			encoder:     LogfmtEncoder{},
+			expectedLog: "time=2020-12-01T12:00:00Z level=WARNING msg=\"random message\" trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7 brand=dito query=\"a=b\"\n",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			out, err := tc.encoder.Encode(entry)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.expectedLog, string(out)); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGCPEncoderFullSpec(t *testing.T) {
+	entry := Entry{
+		Time:    mockedTimmer()(),
+		Level:   LevelInfo,
+		Message: "random message",
+		HTTPRequest: &HTTPRequest{
+			Method:       "GET",
+			URL:          "/users?page=2",
+			Status:       200,
+			ResponseSize: 512,
+			RemoteIP:     "10.0.0.1",
+			Latency:      1500 * time.Millisecond,
+			Protocol:     "HTTP/1.1",
+		},
+	}
+
+	tt := []struct {
+		desc        string
+		encoder     GCPEncoder
+		expectedLog string
+	}{
+		{
+			desc:        "should emit httpRequest and labels",
+			encoder:     GCPEncoder{FullSpec: true, Labels: map[string]string{"service": "api"}},
+			expectedLog: `{"logging.googleapis.com/labels":{"service":"api"},"httpRequest":{"requestMethod":"GET","requestUrl":"/users?page=2","status":200,"responseSize":"512","remoteIp":"10.0.0.1","latency":"1.500000000s","protocol":"HTTP/1.1"},"time":"2020-12-01T12:00:00Z","severity":"INFO","message":"random message"}` + "\n",
+		},
+		{
+			desc:        "should not emit them when the full spec is disabled",
+			encoder:     GCPEncoder{Labels: map[string]string{"service": "api"}},
+			expectedLog: `{"time":"2020-12-01T12:00:00Z","severity":"INFO","message":"random message"}` + "\n",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			out, err := tc.encoder.Encode(entry)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.expectedLog, string(out)); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSourceLocation(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(LoggerInput{Level: "INFO", Writer: &buf, FullSpec: true})
+
+	logger.Info(context.Background(), "random message")
+
+	var data struct {
+		SourceLocation sourceLocationData `json:"logging.googleapis.com/sourceLocation"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !strings.HasSuffix(data.SourceLocation.File, "encoder_test.go") {
+		t.Errorf("expected the source file to be encoder_test.go, got %q", data.SourceLocation.File)
+	}
+
+	if !strings.HasSuffix(data.SourceLocation.Function, "TestSourceLocation") {
+		t.Errorf("expected the source function to be TestSourceLocation, got %q", data.SourceLocation.Function)
+	}
+}
+
+func TestDetectProjectID(t *testing.T) {
+	tt := []struct {
+		desc     string
+		env      map[string]string
+		expected string
+	}{
+		{
+			desc:     "should fall back to new-dito",
+			env:      map[string]string{},
+			expected: "new-dito",
+		},
+		{
+			desc:     "should read GCP_PROJECT",
+			env:      map[string]string{"GCP_PROJECT": "other-project"},
+			expected: "other-project",
+		},
+		{
+			desc:     "should prefer GOOGLE_CLOUD_PROJECT",
+			env:      map[string]string{"GOOGLE_CLOUD_PROJECT": "main-project", "GCLOUD_PROJECT": "other-project"},
+			expected: "main-project",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			for _, envVar := range projectIDEnvVars {
+				t.Setenv(envVar, tc.env[envVar])
+			}
+
+			if got := DetectProjectID(); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoggerWriter(t *testing.T) {
+	var b bytes.Buffer
+
+	logger := NewLogger(LoggerInput{Level: "DEBUG", Writer: &b, Encoder: LogfmtEncoder{}})
+	logger.now = mockedTimmer()
+
+	logger.Info(context.Background(), "random message")
+
+	expected := "time=2020-12-01T12:00:00Z level=INFO msg=\"random message\"\n"
+	if diff := cmp.Diff(expected, b.String()); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -22,4 +22,6 @@ func main() {
 	logger.Warning(ctx, "hello world")
 	logger.Error(ctx, errors.New("random error"))
 	logger.Critical(ctx, errors.New("random error"))
+
+	logger.With("brand", "dito").InfoKV(ctx, "hello world", "attempt", 1)
 }
@@ -0,0 +1,104 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// defaultProjectID is the project used when no other one is given or detected, for backward compatibility.
+const defaultProjectID = "new-dito"
+
+// projectIDEnvVars are the environment variables that may hold the Google Cloud project, in order of precedence.
+var projectIDEnvVars = []string{"GOOGLE_CLOUD_PROJECT", "GCP_PROJECT", "GCLOUD_PROJECT"}
+
+// DetectProjectID returns the Google Cloud project found in the GOOGLE_CLOUD_PROJECT, GCP_PROJECT or GCLOUD_PROJECT
+// environment variables, which should hold the same ProjectID given to trace.Params. It falls back to new-dito.
+func DetectProjectID() string {
+	for _, envVar := range projectIDEnvVars {
+		if projectID := os.Getenv(envVar); projectID != "" {
+			return projectID
+		}
+	}
+
+	return defaultProjectID
+}
+
+// ContextKeyHTTPRequest is the string key under which integrations store the *HTTPRequest being served,
+// so the Logger is able to include it in the logs.
+const ContextKeyHTTPRequest string = "http_request"
+
+// HTTPRequest describes the HTTP request a log is about, as defined by Cloud Logging
+// (https://cloud.google.com/logging/docs/reference/v2/rest/v2/LogEntry#HttpRequest).
+// Response related fields are only known once the request is served.
+type HTTPRequest struct {
+	Method       string
+	URL          string
+	RequestSize  int64
+	Status       int
+	ResponseSize int64
+	UserAgent    string
+	RemoteIP     string
+	Referer      string
+	Latency      time.Duration
+	Protocol     string
+}
+
+func httpRequestFromContext(ctx context.Context) *HTTPRequest {
+	if ctx == nil {
+		return nil
+	}
+
+	req, _ := ctx.Value(ContextKeyHTTPRequest).(*HTTPRequest)
+	return req
+}
+
+type httpRequestData struct {
+	RequestMethod string `json:"requestMethod,omitempty"`
+	RequestURL    string `json:"requestUrl,omitempty"`
+	RequestSize   string `json:"requestSize,omitempty"`
+	Status        int    `json:"status,omitempty"`
+	ResponseSize  string `json:"responseSize,omitempty"`
+	UserAgent     string `json:"userAgent,omitempty"`
+	RemoteIP      string `json:"remoteIp,omitempty"`
+	Referer       string `json:"referer,omitempty"`
+	Latency       string `json:"latency,omitempty"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
+func newHTTPRequestData(req *HTTPRequest) *httpRequestData {
+	if req == nil {
+		return nil
+	}
+
+	data := &httpRequestData{
+		RequestMethod: req.Method,
+		RequestURL:    req.URL,
+		Status:        req.Status,
+		UserAgent:     req.UserAgent,
+		RemoteIP:      req.RemoteIP,
+		Referer:       req.Referer,
+		Protocol:      req.Protocol,
+	}
+
+	if req.RequestSize > 0 {
+		data.RequestSize = fmt.Sprint(req.RequestSize)
+	}
+
+	if req.ResponseSize > 0 {
+		data.ResponseSize = fmt.Sprint(req.ResponseSize)
+	}
+
+	if req.Latency > 0 {
+		data.Latency = fmt.Sprintf("%.9fs", req.Latency.Seconds())
+	}
+
+	return data
+}
+
+type sourceLocationData struct {
+	File     string `json:"file,omitempty"`
+	Line     string `json:"line,omitempty"`
+	Function string `json:"function,omitempty"`
+}
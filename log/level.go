@@ -0,0 +1,130 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// ParseLevel returns the Level of the given name, like INFO or debug
+func ParseLevel(name string) (Level, error) {
+	level, ok := levelStringValueMap[strings.ToUpper(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown log level %q", name)
+	}
+
+	return level, nil
+}
+
+// AtomicLevel is a Level that can be safely read and changed at runtime, while logs are written.
+// It's also an http.Handler that serves the current Level on GET, and changes it on PUT,
+// both using a {"level": "DEBUG"} JSON body.
+type AtomicLevel struct {
+	level int32
+}
+
+// NewAtomicLevel creates a new AtomicLevel set to the given Level
+func NewAtomicLevel(level Level) *AtomicLevel {
+	a := &AtomicLevel{}
+	a.SetLevel(level)
+	return a
+}
+
+// Level returns the current Level
+func (a *AtomicLevel) Level() Level {
+	return Level(atomic.LoadInt32(&a.level))
+}
+
+// SetLevel changes the Level. Invalid Levels are ignored.
+func (a *AtomicLevel) SetLevel(level Level) {
+	if level < LevelCritical || level > LevelDebug {
+		return
+	}
+
+	atomic.StoreInt32(&a.level, int32(level))
+}
+
+type levelPayload struct {
+	Level string `json:"level"`
+}
+
+// ServeHTTP serves the current Level on GET, and changes it on PUT
+func (a *AtomicLevel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var payload levelPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			writeLevelError(w, "invalid request body")
+			return
+		}
+
+		level, err := ParseLevel(payload.Level)
+		if err != nil {
+			writeLevelError(w, err.Error())
+			return
+		}
+
+		a.SetLevel(level)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	json.NewEncoder(w).Encode(levelPayload{Level: a.Level().String()})
+}
+
+func writeLevelError(w http.ResponseWriter, msg string) {
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
+
+// ContextKeyLevel is the string key under which the Level that overrides the Logger one is stored,
+// e.g. to force debug logs for a single request.
+const ContextKeyLevel string = "log_level"
+
+// ContextWithLevel returns a copy of ctx in which logs are written according to the given Level,
+// instead of the Logger one.
+func ContextWithLevel(ctx context.Context, level Level) context.Context {
+	return context.WithValue(ctx, ContextKeyLevel, level)
+}
+
+// LevelOverride returns the Level used for logs written with ctx instead of the Logger one, if any
+type LevelOverride func(ctx context.Context) (Level, bool)
+
+// OverrideLevelByAttribute returns a LevelOverride that applies the given Level to logs written with contexts
+// in which attribute holds value, e.g. OverrideLevelByAttribute("brand", "dito", LevelDebug).
+func OverrideLevelByAttribute(attribute LogAttribute, value interface{}, level Level) LevelOverride {
+	return func(ctx context.Context) (Level, bool) {
+		return level, ctx.Value(string(attribute)) == value
+	}
+}
+
+// enabled reports whether logs of the given Level are written with ctx
+func (l Logger) enabled(ctx context.Context, level Level) bool {
+	return l.levelOf(ctx) >= level
+}
+
+// levelOf returns the Level logs written with ctx are subject to:
+// the one stored under ContextKeyLevel, then the first one given by the LevelOverrides, then the Logger one.
+func (l Logger) levelOf(ctx context.Context) Level {
+	if ctx != nil {
+		if level, ok := ctx.Value(ContextKeyLevel).(Level); ok {
+			return level
+		}
+
+		for _, override := range l.levelOverrides {
+			if level, ok := override(ctx); ok {
+				return level
+			}
+		}
+	}
+
+	return l.level.Level()
+}
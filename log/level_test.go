@@ -0,0 +1,148 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAtomicLevel(t *testing.T) {
+	t.Run("should change the level of the Logger and its children at runtime", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewLogger(LoggerInput{Level: "INFO", Writer: &buf, Encoder: messageEncoder{}})
+		child := logger.With("service", "api")
+
+		logger.Debug(context.Background(), "first message")
+		logger.AtomicLevel().SetLevel(LevelDebug)
+		child.Debug(context.Background(), "second message")
+
+		if diff := cmp.Diff("second message\n", buf.String()); diff != "" {
+			t.Errorf("mismatch (-want, +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should ignore invalid levels", func(t *testing.T) {
+		level := NewAtomicLevel(LevelWarning)
+		level.SetLevel(Level(42))
+
+		if got := level.Level(); got != LevelWarning {
+			t.Errorf("expected WARNING, got %s", got)
+		}
+	})
+}
+
+func TestAtomicLevelServeHTTP(t *testing.T) {
+	tt := []struct {
+		desc               string
+		method             string
+		body               string
+		expectedStatusCode int
+		expectedBody       string
+		expectedLevel      Level
+	}{
+		{
+			desc:               "should serve the current level",
+			method:             http.MethodGet,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"level":"INFO"}` + "\n",
+			expectedLevel:      LevelInfo,
+		},
+		{
+			desc:               "should change the level",
+			method:             http.MethodPut,
+			body:               `{"level":"debug"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"level":"DEBUG"}` + "\n",
+			expectedLevel:      LevelDebug,
+		},
+		{
+			desc:               "should reject unknown levels",
+			method:             http.MethodPut,
+			body:               `{"level":"TRACE"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"error":"unknown log level \"TRACE\""}` + "\n",
+			expectedLevel:      LevelInfo,
+		},
+		{
+			desc:               "should reject other methods",
+			method:             http.MethodPost,
+			body:               `{"level":"DEBUG"}`,
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedLevel:      LevelInfo,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			level := NewAtomicLevel(LevelInfo)
+
+			rec := httptest.NewRecorder()
+			level.ServeHTTP(rec, httptest.NewRequest(tc.method, "/loglevel", strings.NewReader(tc.body)))
+
+			if rec.Code != tc.expectedStatusCode {
+				t.Errorf("expected status code %d, got %d", tc.expectedStatusCode, rec.Code)
+			}
+
+			if diff := cmp.Diff(tc.expectedBody, rec.Body.String()); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+
+			if got := level.Level(); got != tc.expectedLevel {
+				t.Errorf("expected level %s, got %s", tc.expectedLevel, got)
+			}
+		})
+	}
+}
+
+func TestLevelOverrides(t *testing.T) {
+	tt := []struct {
+		desc         string
+		ctx          context.Context
+		expectedLogs string
+	}{
+		{
+			desc:         "should use the Logger level",
+			ctx:          context.Background(),
+			expectedLogs: "warning message\n",
+		},
+		{
+			desc:         "should use the level stored in the context",
+			ctx:          ContextWithLevel(context.Background(), LevelDebug),
+			expectedLogs: "debug message\nwarning message\n",
+		},
+		{
+			desc:         "should use the level of the matching override",
+			ctx:          context.WithValue(context.Background(), "brand", "dito"),
+			expectedLogs: "debug message\nwarning message\n",
+		},
+		{
+			desc:         "should be able to raise the level",
+			ctx:          ContextWithLevel(context.Background(), LevelError),
+			expectedLogs: "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLogger(LoggerInput{
+				Level:          "WARNING",
+				LevelOverrides: []LevelOverride{OverrideLevelByAttribute("brand", "dito", LevelDebug)},
+				Writer:         &buf,
+				Encoder:        messageEncoder{},
+			})
+
+			logger.Debug(tc.ctx, "debug message")
+			logger.Warning(tc.ctx, "warning message")
+
+			if diff := cmp.Diff(tc.expectedLogs, buf.String()); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -2,12 +2,13 @@ package log
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
+	"runtime"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ditointernet/go-dito/errors"
@@ -48,6 +49,14 @@ func (l Level) String() string {
 	}[l-1]
 }
 
+// ReportedErrorEventType is the type of the log entries that carry a stack trace,
+// which makes Cloud Error Reporting pick them up.
+const ReportedErrorEventType = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
+// ContextKeySpan is the string key under which integrations that can't carry typed context keys,
+// like fasthttp request contexts, store the active trace.Span, so the Logger is able to correlate logs with it.
+const ContextKeySpan string = "otel_span"
+
 // LogAttribute represents an information to be extracted from the context and included into the log
 type LogAttribute string
 
@@ -56,152 +65,389 @@ type LogAttributeSet map[LogAttribute]bool
 
 // LoggerInput defines the dependencies of a Logger
 type LoggerInput struct {
-	Level      string
-	Attributes LogAttributeSet
+	// Level is the name of the minimum Level of the logs to be written. Defaults to INFO.
+	Level string
+	// AtomicLevel, when given, replaces Level, so the Logger level can be changed at runtime.
+	AtomicLevel *AtomicLevel
+	// LevelOverrides replace the Logger level for the contexts they match, the first match winning.
+	LevelOverrides []LevelOverride
+	Attributes     LogAttributeSet
+	// Writer is where logs are written to. Defaults to os.Stdout. Wrap it with NewAsyncWriter to write in background.
+	// Writes are serialized by the Logger and its children, so it doesn't need to be safe for concurrent use.
+	Writer io.Writer
+	// Encoder serializes each log entry. Defaults to a GCPEncoder built with ProjectID, FullSpec and Labels.
+	Encoder Encoder
+	// ProjectID is the Google Cloud project that hosts the traces logs are correlated with.
+	// Defaults to the one found by DetectProjectID. It's ignored when an Encoder is given.
+	ProjectID string
+	// FullSpec makes the default GCPEncoder emit sourceLocation, httpRequest and labels fields.
+	// It's ignored when an Encoder is given.
+	FullSpec bool
+	// Labels are emitted along with every log when FullSpec is enabled. It's ignored when an Encoder is given.
+	Labels map[string]string
+	// Redactor, when given, masks sensitive data in messages, attributes and span events.
+	Redactor *Redactor
+	// Sampling limits how many logs of the same level and message template are written. Nil disables sampling.
+	Sampling *SamplingInput
 }
 
 // Logger is the structure responsible for log data
 type Logger struct {
-	level      Level
-	attributes LogAttributeSet
-	now        func() time.Time
+	level          *AtomicLevel
+	levelOverrides []LevelOverride
+	attributes     LogAttributeSet
+	now            func() time.Time
+	writer         io.Writer
+	writerMux      *sync.Mutex
+	encoder        Encoder
+	fields         map[LogAttribute]interface{}
+	sampler        *sampler
+	redactor       *Redactor
 }
 
 // NewLogger constructs a new Logger instance
 func NewLogger(in LoggerInput) *Logger {
-	logger := &Logger{level: levelStringValueMap[in.Level], attributes: in.Attributes, now: time.Now}
+	if in.Writer == nil {
+		in.Writer = stdoutWriter{}
+	}
+
+	if in.Encoder == nil {
+		if in.ProjectID == "" {
+			in.ProjectID = DetectProjectID()
+		}
+
+		in.Encoder = GCPEncoder{ProjectID: in.ProjectID, FullSpec: in.FullSpec, Labels: in.Labels}
+	}
+
+	if in.AtomicLevel == nil {
+		level, err := ParseLevel(in.Level)
+		if err != nil {
+			level = LevelInfo
+		}
+
+		in.AtomicLevel = NewAtomicLevel(level)
+	}
 
-	if logger.level < LevelCritical || logger.level > LevelDebug {
-		logger.level = LevelInfo
+	logger := &Logger{
+		level:          in.AtomicLevel,
+		levelOverrides: in.LevelOverrides,
+		attributes:     in.Attributes,
+		now:            time.Now,
+		writer:         in.Writer,
+		writerMux:      &sync.Mutex{},
+		encoder:        in.Encoder,
+		redactor:       in.Redactor,
+	}
+
+	if in.Sampling != nil {
+		logger.sampler = newSampler(*in.Sampling)
+		go logger.sampler.run(func() { logger.reportDroppedLogs(logger.now()) })
 	}
 
 	return logger
 }
 
+// AtomicLevel returns the AtomicLevel of the Logger, which is shared with its children.
+// Since it's an http.Handler, it can be mounted to read and change the level at runtime.
+func (l Logger) AtomicLevel() *AtomicLevel {
+	return l.level
+}
+
+// With returns a child Logger that includes the given key/value pairs as attributes of every log.
+// Keys should be strings, and a key without a value is logged under the !BADKEY key.
+func (l Logger) With(keysAndValues ...interface{}) *Logger {
+	l.fields = mergeFields(l.fields, keysAndValues)
+	return &l
+}
+
 // Debug logs debug data
 func (l Logger) Debug(ctx context.Context, msg string, args ...interface{}) {
-	if l.level >= LevelDebug {
-		l.print(ctx, fmt.Sprintf(msg, args...), LevelDebug)
+	if l.enabled(ctx, LevelDebug) {
+		l.print(ctx, msg, fmt.Sprintf(msg, args...), LevelDebug, l.fields)
+	}
+}
+
+// DebugKV logs debug data with the given key/value pairs as attributes
+func (l Logger) DebugKV(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if l.enabled(ctx, LevelDebug) {
+		l.print(ctx, msg, msg, LevelDebug, mergeFields(l.fields, keysAndValues))
 	}
 }
 
 // Info logs info data
 func (l Logger) Info(ctx context.Context, msg string, args ...interface{}) {
-	if l.level >= LevelInfo {
-		l.print(ctx, fmt.Sprintf(msg, args...), LevelInfo)
+	if l.enabled(ctx, LevelInfo) {
+		l.print(ctx, msg, fmt.Sprintf(msg, args...), LevelInfo, l.fields)
+	}
+}
+
+// InfoKV logs info data with the given key/value pairs as attributes
+func (l Logger) InfoKV(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if l.enabled(ctx, LevelInfo) {
+		l.print(ctx, msg, msg, LevelInfo, mergeFields(l.fields, keysAndValues))
 	}
 }
 
 // Warning logs warning data
 func (l Logger) Warning(ctx context.Context, msg string, args ...interface{}) {
-	if l.level >= LevelWarning {
-		l.print(ctx, fmt.Sprintf(msg, args...), LevelWarning)
+	if l.enabled(ctx, LevelWarning) {
+		l.print(ctx, msg, fmt.Sprintf(msg, args...), LevelWarning, l.fields)
+	}
+}
+
+// WarningKV logs warning data with the given key/value pairs as attributes
+func (l Logger) WarningKV(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if l.enabled(ctx, LevelWarning) {
+		l.print(ctx, msg, msg, LevelWarning, mergeFields(l.fields, keysAndValues))
 	}
 }
 
-// Error logs error data
+// Error logs error data. Each error of an errors.MultiError is logged on its own
 func (l Logger) Error(ctx context.Context, err error) {
-	if l.level >= LevelError {
-		l.printError(ctx, err, LevelError)
+	if l.enabled(ctx, LevelError) {
+		l.printError(ctx, err, LevelError, l.fields)
 	}
 }
 
-// Critical logs critical data
+// ErrorKV logs error data with the given key/value pairs as attributes
+func (l Logger) ErrorKV(ctx context.Context, err error, keysAndValues ...interface{}) {
+	if l.enabled(ctx, LevelError) {
+		l.printError(ctx, err, LevelError, mergeFields(l.fields, keysAndValues))
+	}
+}
+
+// Critical logs critical data. Each error of an errors.MultiError is logged on its own
 func (l Logger) Critical(ctx context.Context, err error) {
-	if l.level >= LevelCritical {
-		l.printError(ctx, err, LevelCritical)
+	if l.enabled(ctx, LevelCritical) {
+		l.printError(ctx, err, LevelCritical, l.fields)
 	}
 }
 
-type logData struct {
-	Trace        string                       `json:"logging.googleapis.com/trace,omitempty"`
-	SpanID       string                       `json:"logging.googleapis.com/spanId,omitempty"`
-	TraceSampled *bool                        `json:"logging.googleapis.com/trace_sampled,omitempty"`
-	Timestamp    string                       `json:"time"`
-	Level        string                       `json:"severity"`
-	Message      string                       `json:"message"`
-	Attributes   map[LogAttribute]interface{} `json:"attributes,omitempty"`
+// CriticalKV logs critical data with the given key/value pairs as attributes
+func (l Logger) CriticalKV(ctx context.Context, err error, keysAndValues ...interface{}) {
+	if l.enabled(ctx, LevelCritical) {
+		l.printError(ctx, err, LevelCritical, mergeFields(l.fields, keysAndValues))
+	}
 }
 
-func (l Logger) print(ctx context.Context, msg string, level Level) {
-	span := trace.SpanFromContext(ctx)
+func (l Logger) print(ctx context.Context, template, msg string, level Level, fields map[LogAttribute]interface{}) {
+	span := spanFromContext(ctx)
 
 	attrs := l.extractLogAttributesFromContext(ctx)
+	for key, value := range fields {
+		attrs[key] = value
+	}
+	msg = l.redact(msg, attrs)
+
+	if !l.sample(level, template, span.SpanContext()) {
+		return
+	}
 
 	span.AddEvent("log", trace.WithAttributes(buildOtelAttributes(attrs, "log")...))
 
-	data, _ := json.Marshal(logData{
-		Trace:        getTrace(span),
-		SpanID:       getSpanID(span),
-		TraceSampled: getIsTraceSampled(span),
-		Timestamp:    l.now().Format(time.RFC3339),
-		Level:        level.String(),
-		Message:      msg,
-		Attributes:   attrs,
+	l.write(Entry{
+		Time:        l.now(),
+		Level:       level,
+		Message:     msg,
+		Attributes:  attrs,
+		SpanContext: span.SpanContext(),
+		PC:          callerPC(4),
+		HTTPRequest: l.httpRequest(ctx),
 	})
+}
 
-	fmt.Println(string(data))
+func (l Logger) printError(ctx context.Context, err error, level Level, fields map[LogAttribute]interface{}) {
+	l.printErrorFrom(ctx, callerPC(4), err, level, fields)
 }
 
-func (l Logger) printError(ctx context.Context, err error, level Level) {
-	span := trace.SpanFromContext(ctx)
+func (l Logger) printErrorFrom(ctx context.Context, pc uintptr, err error, level Level, fields map[LogAttribute]interface{}) {
+	if multiErr, ok := err.(errors.MultiError); ok {
+		for _, entry := range multiErr.Errors() {
+			l.printErrorFrom(ctx, pc, entry, level, fields)
+		}
+		return
+	}
+
+	span := spanFromContext(ctx)
 
 	attrs := l.extractLogAttributesFromContext(ctx)
+	for key, value := range fields {
+		attrs[key] = value
+	}
+	for key, value := range errors.Fields(err) {
+		attrs[LogAttribute(key)] = value
+	}
 	attrs["kind"] = string(errors.Kind(err))
 	attrs["code"] = string(errors.Code(err))
+	if errors.IsRetryable(err) {
+		attrs["retryable"] = true
+	}
+	msg := l.redact(err.Error(), attrs)
+
+	eAttrs := buildOtelAttributes(attrs, "exception")
 
-	span.RecordError(err, trace.WithAttributes(buildOtelAttributes(attrs, "exception")...))
-	span.SetStatus(codes.Error, err.Error())
-
-	data, _ := json.Marshal(logData{
-		Trace:        getTrace(span),
-		SpanID:       getSpanID(span),
-		TraceSampled: getIsTraceSampled(span),
-		Timestamp:    l.now().Format(time.RFC3339),
-		Level:        level.String(),
-		Message:      err.Error(),
-		Attributes:   attrs,
+	stack := errors.StackTrace(err)
+	if stack != "" {
+		eAttrs = append(eAttrs, attribute.String("exception.stacktrace", stack))
+	}
+
+	// Uncoded errors are sampled by message, so unrelated failures don't share the CodeUnknown counter.
+	template := msg
+	if code := errors.Code(err); code != errors.CodeUnknown {
+		template = string(code)
+	}
+
+	if !l.sample(level, template, span.SpanContext()) {
+		return
+	}
+
+	l.recordError(span, err, msg, eAttrs)
+
+	l.write(Entry{
+		Time:        l.now(),
+		Level:       level,
+		Message:     msg,
+		Attributes:  attrs,
+		SpanContext: span.SpanContext(),
+		StackTrace:  stack,
+		PC:          pc,
+		HTTPRequest: l.httpRequest(ctx),
 	})
+}
+
+func (l Logger) write(entry Entry) {
+	data, err := l.encoder.Encode(entry)
+	if err != nil {
+		return
+	}
+
+	l.writerMux.Lock()
+	defer l.writerMux.Unlock()
+
+	l.writer.Write(data)
+}
+
+// sample reports whether a log should be written, according to the sampling of the Logger.
+func (l Logger) sample(level Level, template string, sc trace.SpanContext) bool {
+	if l.sampler == nil {
+		return true
+	}
+
+	return l.sampler.sample(l.now(), samplingKey{level: level, template: template}, sc)
+}
+
+// reportDroppedLogs writes a warning for each level and message template with logs dropped by sampling.
+func (l Logger) reportDroppedLogs(now time.Time) {
+	for _, dropped := range l.sampler.droppedSince(now) {
+		l.write(Entry{
+			Time:    now,
+			Level:   LevelWarning,
+			Message: fmt.Sprintf("log sampling dropped %d %s logs", dropped.count, dropped.key.level),
+			Attributes: map[LogAttribute]interface{}{
+				"sampled_level":    dropped.key.level.String(),
+				"sampled_template": dropped.key.template,
+				"dropped":          dropped.count,
+			},
+		})
+	}
+}
+
+// Flush reports the logs dropped by sampling so far, then flushes the Writer of the Logger,
+// when it buffers data, like AsyncWriter does. It should be called before the application exits.
+func (l Logger) Flush() error {
+	if l.sampler != nil {
+		l.reportDroppedLogs(l.now())
+	}
 
-	fmt.Println(string(data))
+	if f, ok := l.writer.(Flusher); ok {
+		return f.Flush()
+	}
+
+	return nil
 }
 
-func getTrace(span trace.Span) string {
-	if !span.SpanContext().TraceID().IsValid() {
-		return ""
+// Close stops the background reports of the logs dropped by sampling, which are shared with the children of
+// the Logger, then flushes it. The Logger can still be used afterwards, but dropped logs are only reported by Flush.
+func (l Logger) Close() error {
+	if l.sampler != nil {
+		l.sampler.close()
 	}
 
-	return fmt.Sprintf("projects/new-dito/traces/%s", span.SpanContext().TraceID().String())
+	return l.Flush()
 }
 
-func getSpanID(span trace.Span) string {
-	if !span.SpanContext().TraceID().IsValid() {
-		return ""
+// callerPC returns the program counter of the function that called the Logger,
+// skip being the number of frames between it and callerPC, including runtime.Callers.
+func callerPC(skip int) uintptr {
+	var pcs [1]uintptr
+	if runtime.Callers(skip, pcs[:]) == 0 {
+		return 0
 	}
 
-	return span.SpanContext().SpanID().String()
+	return pcs[0]
 }
 
-func getIsTraceSampled(span trace.Span) *bool {
-	if !span.SpanContext().TraceID().IsValid() {
-		return nil
+func spanFromContext(ctx context.Context) trace.Span {
+	span := trace.SpanFromContext(ctx)
+	if span.SpanContext().IsValid() || ctx == nil {
+		return span
+	}
+
+	if s, ok := ctx.Value(ContextKeySpan).(trace.Span); ok {
+		return s
 	}
 
-	isSampled := span.SpanContext().IsSampled()
-	return &isSampled
+	return span
 }
 
 func buildOtelAttributes(attrs map[LogAttribute]interface{}, prefix string) []attribute.KeyValue {
 	eAttrs := []attribute.KeyValue{}
 	for k, v := range attrs {
-		eAttrs = append(eAttrs, attribute.String(fmt.Sprintf("%s.%s", prefix, k), v.(string)))
+		eAttrs = append(eAttrs, buildOtelAttribute(fmt.Sprintf("%s.%s", prefix, k), v))
 	}
 
 	return eAttrs
 }
 
+// buildOtelAttribute preserves the type of the value when OpenTelemetry supports it, and stringifies it otherwise.
+func buildOtelAttribute(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case []int:
+		return attribute.IntSlice(key, v)
+	case []bool:
+		return attribute.BoolSlice(key, v)
+	case []float64:
+		return attribute.Float64Slice(key, v)
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
+
 func (l Logger) extractLogAttributesFromContext(ctx context.Context) map[LogAttribute]interface{} {
 	attributes := map[LogAttribute]interface{}{}
+	if ctx == nil {
+		return attributes
+	}
 
 	for attr := range l.attributes {
 		if value := ctx.Value(string(attr)); value != nil {
@@ -211,3 +457,33 @@ func (l Logger) extractLogAttributesFromContext(ctx context.Context) map[LogAttr
 
 	return attributes
 }
+
+// badKey is the key of values given without a key, like in slog.
+const badKey = "!BADKEY"
+
+// mergeFields returns a copy of fields with the given key/value pairs included.
+func mergeFields(fields map[LogAttribute]interface{}, keysAndValues []interface{}) map[LogAttribute]interface{} {
+	if len(keysAndValues) == 0 {
+		return fields
+	}
+
+	merged := make(map[LogAttribute]interface{}, len(fields)+len(keysAndValues)/2)
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 == len(keysAndValues) {
+			merged[badKey] = keysAndValues[i]
+			break
+		}
+
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		merged[LogAttribute(key)] = keysAndValues[i+1]
+	}
+
+	return merged
+}
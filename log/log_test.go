@@ -1,15 +1,21 @@
 package log
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/trace"
+
+	ditoErrors "github.com/ditointernet/go-dito/errors"
 )
 
 func mockedTimmer() func() time.Time {
@@ -340,6 +346,27 @@ func TestError(t *testing.T) {
 			attrs:       LogAttributeSet{"attr1": true},
 			expectedLog: `{"time":"2020-12-01T12:00:00Z","severity":"ERROR","message":"random error","attributes":{"attr1":"value1","code":"UNKNOWN","kind":"UNEXPECTED"}}`,
 		},
+		{
+			desc:        "should log the error metadata as attributes",
+			ctx:         ctx,
+			level:       "DEBUG",
+			err:         ditoErrors.New("random error").WithField("brand_id", "dito").WithField("status", 404),
+			expectedLog: `{"time":"2020-12-01T12:00:00Z","severity":"ERROR","message":"random error","attributes":{"brand_id":"dito","code":"UNKNOWN","kind":"UNEXPECTED","status":404}}`,
+		},
+		{
+			desc:        "should log every error of a MultiError",
+			ctx:         ctx,
+			level:       "DEBUG",
+			err:         ditoErrors.NewMultiError(errors.New("random error"), ditoErrors.New("another error").WithKind(ditoErrors.KindNotFound)),
+			expectedLog: `{"time":"2020-12-01T12:00:00Z","severity":"ERROR","message":"random error","attributes":{"code":"UNKNOWN","kind":"UNEXPECTED"}}` + "\n" + `{"time":"2020-12-01T12:00:00Z","severity":"ERROR","message":"another error","attributes":{"code":"UNKNOWN","kind":"NOT_FOUND"}}`,
+		},
+		{
+			desc:        "should flag retryable errors",
+			ctx:         ctx,
+			level:       "DEBUG",
+			err:         ditoErrors.New("random error").WithKind(ditoErrors.KindUnavailable),
+			expectedLog: `{"time":"2020-12-01T12:00:00Z","severity":"ERROR","message":"random error","attributes":{"code":"UNKNOWN","kind":"UNAVAILABLE","retryable":true}}`,
+		},
 	}
 
 	for _, tc := range tt {
@@ -430,6 +457,32 @@ func TestCritical(t *testing.T) {
 	}
 }
 
+func TestSpanCorrelation(t *testing.T) {
+	t.Run("should correlate logs with a span stored under ContextKeySpan", func(t *testing.T) {
+		traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+		spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+		span := trace.SpanFromContext(trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+		})))
+
+		ctx := context.WithValue(context.Background(), ContextKeySpan, span)
+
+		logger := NewLogger(LoggerInput{})
+		logger.now = mockedTimmer()
+
+		out := captureOutput(func() {
+			logger.Info(ctx, "random message")
+		})
+
+		expected := `{"logging.googleapis.com/trace":"projects/new-dito/traces/4bf92f3577b34da6a3ce929d0e0e4736","logging.googleapis.com/spanId":"00f067aa0ba902b7","logging.googleapis.com/trace_sampled":true,"time":"2020-12-01T12:00:00Z","severity":"INFO","message":"random message"}`
+		if diff := cmp.Diff(expected, out); diff != "" {
+			t.Errorf("mismatch (-want, +got):\n%s", diff)
+		}
+	})
+}
+
 func captureOutput(output func()) string {
 	rescueStdout := os.Stdout
 	r, w, _ := os.Pipe()
@@ -443,3 +496,123 @@ func captureOutput(output func()) string {
 
 	return strings.TrimRight(string(out), "\n")
 }
+
+func TestErrorStackTrace(t *testing.T) {
+	logger := NewLogger(LoggerInput{Level: "DEBUG"})
+	logger.now = mockedTimmer()
+
+	out := captureOutput(func() {
+		logger.Error(context.Background(), ditoErrors.New("random error").WithStack())
+	})
+
+	var data logData
+	if err := json.Unmarshal([]byte(out), &data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if data.Type != ReportedErrorEventType {
+		t.Errorf("expected type '%s', got '%s'", ReportedErrorEventType, data.Type)
+	}
+
+	expectedPrefix := "random error\n\ngoroutine 1 [running]:\ngithub.com/ditointernet/go-dito/log.TestErrorStackTrace.func1(...)\n"
+	if !strings.HasPrefix(data.StackTrace, expectedPrefix) {
+		t.Errorf("expected stack trace to start with %q, got %q", expectedPrefix, data.StackTrace)
+	}
+}
+
+func TestKeyValueFields(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "retries", 3)
+
+	tt := []struct {
+		desc        string
+		log         func(logger *Logger)
+		expectedLog string
+	}{
+		{
+			desc: "should log the fields of child loggers",
+			log: func(logger *Logger) {
+				logger.With("brand", "dito").With("enabled", true).Info(ctx, "random message %d", 1)
+			},
+			expectedLog: `{"time":"2020-12-01T12:00:00Z","severity":"INFO","message":"random message 1","attributes":{"brand":"dito","enabled":true,"retries":3}}`,
+		},
+		{
+			desc: "should log the fields of the call, overriding the ones of the logger",
+			log: func(logger *Logger) {
+				logger.With("brand", "dito").InfoKV(ctx, "random message", "brand", "other", "elapsed", 1.5)
+			},
+			expectedLog: `{"time":"2020-12-01T12:00:00Z","severity":"INFO","message":"random message","attributes":{"brand":"other","elapsed":1.5,"retries":3}}`,
+		},
+		{
+			desc: "should log values without keys under !BADKEY",
+			log: func(logger *Logger) {
+				logger.WarningKV(ctx, "random message", "brand")
+			},
+			expectedLog: `{"time":"2020-12-01T12:00:00Z","severity":"WARNING","message":"random message","attributes":{"!BADKEY":"brand","retries":3}}`,
+		},
+		{
+			desc: "should log the fields along with the error ones",
+			log: func(logger *Logger) {
+				logger.ErrorKV(ctx, errors.New("random error"), "brand", "dito")
+			},
+			expectedLog: `{"time":"2020-12-01T12:00:00Z","severity":"ERROR","message":"random error","attributes":{"brand":"dito","code":"UNKNOWN","kind":"UNEXPECTED","retries":3}}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			logger := NewLogger(LoggerInput{Level: "DEBUG", Attributes: LogAttributeSet{"retries": true}})
+			logger.now = mockedTimmer()
+
+			out := captureOutput(func() {
+				tc.log(logger)
+			})
+
+			if diff := cmp.Diff(tc.expectedLog, out); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(LoggerInput{Writer: &buf, Encoder: GCPEncoder{ProjectID: "project"}})
+	child := logger.With("brand", "dito")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() { defer wg.Done(); logger.Info(context.Background(), "parent message") }()
+		go func() { defer wg.Done(); child.Info(context.Background(), "child message") }()
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 100 {
+		t.Fatalf("expected 100 logs, got %d", len(lines))
+	}
+
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Errorf("expected every log to be a valid JSON, got %s", line)
+		}
+	}
+}
+
+func TestNilContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(LoggerInput{
+		Level:      "DEBUG",
+		Writer:     &buf,
+		Encoder:    GCPEncoder{ProjectID: "project", FullSpec: true},
+		Attributes: LogAttributeSet{"brand": true},
+	})
+	logger.now = mockedTimmer()
+
+	logger.Info(nil, "random message")
+	logger.Error(nil, errors.New("random error"))
+
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 2 {
+		t.Errorf("expected 2 logs, got %d", len(lines))
+	}
+}
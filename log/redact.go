@@ -0,0 +1,268 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultRedactionMask replaces the sensitive data found by a Redactor
+const DefaultRedactionMask = "[REDACTED]"
+
+var (
+	// PatternJWT matches JSON Web Tokens
+	PatternJWT = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	// PatternEmail matches email addresses
+	PatternEmail = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// PatternCPF matches CPFs, with or without punctuation. Only numbers with valid check digits are masked
+	PatternCPF = regexp.MustCompile(`\b\d{3}\.?\d{3}\.?\d{3}-?\d{2}\b`)
+	// PatternCNPJ matches CNPJs, with or without punctuation. Only numbers with valid check digits are masked
+	PatternCNPJ = regexp.MustCompile(`\b\d{2}\.?\d{3}\.?\d{3}/?\d{4}-?\d{2}\b`)
+	// PatternCardNumber matches payment card numbers, optionally grouped by spaces or dashes.
+	// Only numbers that pass the Luhn check are masked
+	PatternCardNumber = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+)
+
+// patternValidators tell the actual sensitive data apart from other matches of the patterns that are too broad,
+// like timestamps and ids matched by PatternCardNumber
+var patternValidators = map[*regexp.Regexp]func(digits []int) bool{
+	PatternCPF:        validCPF,
+	PatternCNPJ:       validCNPJ,
+	PatternCardNumber: validLuhn,
+}
+
+// DefaultRedactionPatterns are the patterns of the sensitive data usually found in messages
+var DefaultRedactionPatterns = []*regexp.Regexp{PatternJWT, PatternEmail, PatternCNPJ, PatternCPF, PatternCardNumber}
+
+// RedactorInput defines the dependencies of a Redactor
+type RedactorInput struct {
+	// Keys are the attributes, matched case insensitively, whose values are masked as a whole
+	Keys []string
+	// Patterns match the sensitive data masked within messages and attribute values,
+	// e.g. DefaultRedactionPatterns
+	Patterns []*regexp.Regexp
+	// Mask replaces the sensitive data. Defaults to DefaultRedactionMask
+	Mask string
+}
+
+// Redactor masks sensitive data, like tokens, emails and documents, before it leaves the application
+type Redactor struct {
+	keys     map[string]bool
+	patterns []*regexp.Regexp
+	mask     string
+}
+
+// NewRedactor creates a new Redactor
+func NewRedactor(in RedactorInput) *Redactor {
+	if in.Mask == "" {
+		in.Mask = DefaultRedactionMask
+	}
+
+	keys := make(map[string]bool, len(in.Keys))
+	for _, key := range in.Keys {
+		keys[strings.ToLower(key)] = true
+	}
+
+	return &Redactor{
+		keys:     keys,
+		patterns: in.Patterns,
+		mask:     in.Mask,
+	}
+}
+
+// Redact masks the parts of s matched by the Redactor patterns. A nil Redactor keeps s as is.
+func (r *Redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+
+	for _, pattern := range r.patterns {
+		valid, ok := patternValidators[pattern]
+		if !ok {
+			s = pattern.ReplaceAllLiteralString(s, r.mask)
+			continue
+		}
+
+		s = pattern.ReplaceAllStringFunc(s, func(match string) string {
+			if valid(digitsOf(match)) {
+				return r.mask
+			}
+			return match
+		})
+	}
+
+	return s
+}
+
+// RedactValue masks the value of the given attribute key. Values of configured keys are masked as a whole,
+// while strings and errors have the parts matched by the patterns masked. A nil Redactor keeps value as is.
+func (r *Redactor) RedactValue(key string, value interface{}) interface{} {
+	if r == nil {
+		return value
+	}
+
+	if r.keys[strings.ToLower(key)] {
+		return r.mask
+	}
+
+	switch v := value.(type) {
+	case string:
+		return r.Redact(v)
+	case error:
+		return r.Redact(v.Error())
+	default:
+		return value
+	}
+}
+
+// redactURL masks the sensitive data of the path and of the query values of rawURL, the latter being masked
+// as a whole when their keys are configured, like attributes are
+func (r *Redactor) redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return r.Redact(rawURL)
+	}
+
+	u.Path, u.RawPath = r.Redact(u.Path), ""
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key, values := range query {
+			for i, value := range values {
+				values[i] = fmt.Sprint(r.RedactValue(key, value))
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String()
+}
+
+// httpRequest returns the HTTPRequest of ctx, with the sensitive data of its URLs masked
+func (l Logger) httpRequest(ctx context.Context) *HTTPRequest {
+	req := httpRequestFromContext(ctx)
+	if req == nil || l.redactor == nil {
+		return req
+	}
+
+	redacted := *req
+	redacted.URL = l.redactor.redactURL(req.URL)
+	if req.Referer != "" {
+		redacted.Referer = l.redactor.redactURL(req.Referer)
+	}
+
+	return &redacted
+}
+
+// redact masks the sensitive data of the message and attributes of a log, which are changed in place
+func (l Logger) redact(msg string, attrs map[LogAttribute]interface{}) string {
+	if l.redactor == nil {
+		return msg
+	}
+
+	for key, value := range attrs {
+		attrs[key] = l.redactor.RedactValue(string(key), value)
+	}
+
+	return l.redactor.Redact(msg)
+}
+
+// recordError records err in span as an exception event, with its message redacted.
+// It mirrors trace.Span RecordError, which always records the message verbatim.
+func (l Logger) recordError(span trace.Span, err error, msg string, attrs []attribute.KeyValue) {
+	if l.redactor == nil {
+		span.RecordError(err, trace.WithAttributes(attrs...))
+		span.SetStatus(codes.Error, msg)
+		return
+	}
+
+	eAttrs := append([]attribute.KeyValue{
+		attribute.String("exception.type", errorType(err)),
+		attribute.String("exception.message", msg),
+	}, attrs...)
+
+	span.AddEvent("exception", trace.WithAttributes(eAttrs...))
+	span.SetStatus(codes.Error, msg)
+}
+
+func errorType(err error) string {
+	t := reflect.TypeOf(err)
+	if t.PkgPath() == "" && t.Name() == "" {
+		return t.String()
+	}
+
+	return t.PkgPath() + "." + t.Name()
+}
+
+// digitsOf returns the digits of s, ignoring its punctuation
+func digitsOf(s string) []int {
+	digits := make([]int, 0, len(s))
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			digits = append(digits, int(c-'0'))
+		}
+	}
+
+	return digits
+}
+
+// validLuhn reports whether digits pass the Luhn check, used by payment card numbers
+func validLuhn(digits []int) bool {
+	sum := 0
+	for i := range digits {
+		d := digits[len(digits)-1-i]
+		if i%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+
+	return sum%10 == 0
+}
+
+// validCPF reports whether digits are a CPF with valid check digits
+func validCPF(digits []int) bool {
+	return len(digits) == 11 && !repeated(digits) &&
+		checkDigit(digits[:9], []int{10, 9, 8, 7, 6, 5, 4, 3, 2}) == digits[9] &&
+		checkDigit(digits[:10], []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2}) == digits[10]
+}
+
+// validCNPJ reports whether digits are a CNPJ with valid check digits
+func validCNPJ(digits []int) bool {
+	return len(digits) == 14 && !repeated(digits) &&
+		checkDigit(digits[:12], []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == digits[12] &&
+		checkDigit(digits[:13], []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == digits[13]
+}
+
+// checkDigit computes the modulo 11 check digit of digits, weighted by weights
+func checkDigit(digits, weights []int) int {
+	sum := 0
+	for i, d := range digits {
+		sum += d * weights[i]
+	}
+
+	if rest := sum % 11; rest >= 2 {
+		return 11 - rest
+	}
+
+	return 0
+}
+
+// repeated reports whether all digits are the same. Such numbers have valid check digits, but aren't issued
+func repeated(digits []int) bool {
+	for _, d := range digits {
+		if d != digits[0] {
+			return false
+		}
+	}
+
+	return true
+}
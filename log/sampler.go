@@ -0,0 +1,167 @@
+package log
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SamplingInput configures the sampling of logs. Within each Interval, the first Initial logs of a given level and
+// message template are written, then only one out of every Thereafter of them. Errors are grouped by code instead,
+// or by message when they have none. Errors and criticals of sampled traces are always written,
+// so every error of a trace that is kept can be found in the logs.
+type SamplingInput struct {
+	// Initial is the number of logs written per Interval before sampling kicks in
+	Initial int
+	// Thereafter makes one out of every Thereafter logs be written after the Initial ones. Zero drops all of them
+	Thereafter int
+	// Interval is the period after which counters are reset. Defaults to 1 second
+	Interval time.Duration
+	// ReportInterval is the period between reports of the number of dropped logs, which are written in background
+	// until the Logger is closed. Defaults to 1 minute
+	ReportInterval time.Duration
+}
+
+const (
+	defaultSamplingInterval       = time.Second
+	defaultSamplingReportInterval = time.Minute
+)
+
+// samplingKey identifies the logs that are sampled together
+type samplingKey struct {
+	level    Level
+	template string
+}
+
+type samplingCounter struct {
+	resetAt time.Time
+	count   int
+}
+
+// sampler counts logs by samplingKey, deciding which are written and how many are dropped.
+// It's shared by a Logger and its children.
+type sampler struct {
+	initial        int
+	thereafter     int
+	interval       time.Duration
+	reportInterval time.Duration
+
+	mux      sync.Mutex
+	counters map[samplingKey]*samplingCounter
+	dropped  map[samplingKey]int
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	stopped  chan struct{}
+}
+
+func newSampler(in SamplingInput) *sampler {
+	if in.Interval <= 0 {
+		in.Interval = defaultSamplingInterval
+	}
+
+	if in.ReportInterval <= 0 {
+		in.ReportInterval = defaultSamplingReportInterval
+	}
+
+	return &sampler{
+		initial:        in.Initial,
+		thereafter:     in.Thereafter,
+		interval:       in.Interval,
+		reportInterval: in.ReportInterval,
+		counters:       map[samplingKey]*samplingCounter{},
+		dropped:        map[samplingKey]int{},
+		stop:           make(chan struct{}),
+		stopped:        make(chan struct{}),
+	}
+}
+
+// run calls report every ReportInterval, until the sampler is stopped.
+func (s *sampler) run(report func()) {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(s.reportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			report()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// close stops the periodic reports and waits for the one in progress, if any.
+func (s *sampler) close() {
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.stopped
+}
+
+// sample reports whether a log should be written, counting it as dropped when it shouldn't.
+func (s *sampler) sample(now time.Time, key samplingKey, sc trace.SpanContext) bool {
+	if key.level <= LevelError && sc.IsSampled() {
+		return true
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	counter, ok := s.counters[key]
+	if !ok || !now.Before(counter.resetAt) {
+		counter = &samplingCounter{resetAt: now.Add(s.interval)}
+		s.counters[key] = counter
+	}
+
+	counter.count++
+	if counter.count <= s.initial {
+		return true
+	}
+
+	if s.thereafter > 0 && (counter.count-s.initial)%s.thereafter == 0 {
+		return true
+	}
+
+	s.dropped[key]++
+	return false
+}
+
+// droppedSince returns the logs dropped since the last report, sorted by level and template.
+// Counters that are no longer in use are released along the way.
+func (s *sampler) droppedSince(now time.Time) []droppedLogs {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for key, counter := range s.counters {
+		if !now.Before(counter.resetAt) {
+			delete(s.counters, key)
+		}
+	}
+
+	if len(s.dropped) == 0 {
+		return nil
+	}
+
+	reports := make([]droppedLogs, 0, len(s.dropped))
+	for key, count := range s.dropped {
+		reports = append(reports, droppedLogs{key: key, count: count})
+	}
+	s.dropped = map[samplingKey]int{}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].key.level != reports[j].key.level {
+			return reports[i].key.level < reports[j].key.level
+		}
+		return reports[i].key.template < reports[j].key.template
+	})
+
+	return reports
+}
+
+type droppedLogs struct {
+	key   samplingKey
+	count int
+}
@@ -0,0 +1,198 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/trace"
+
+	ditoErrors "github.com/ditointernet/go-dito/errors"
+)
+
+func TestSampling(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	sampledCtx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	tt := []struct {
+		desc         string
+		log          func(logger *Logger, clock *time.Time)
+		expectedLogs []string
+	}{
+		{
+			desc: "should write the initial logs, then one out of every thereafter",
+			log: func(logger *Logger, clock *time.Time) {
+				for i := 1; i <= 7; i++ {
+					logger.Info(context.Background(), "message %d", i)
+				}
+			},
+			expectedLogs: []string{"message 1", "message 2", "message 5"},
+		},
+		{
+			desc: "should sample each message template and level on its own",
+			log: func(logger *Logger, clock *time.Time) {
+				for i := 1; i <= 3; i++ {
+					logger.Info(context.Background(), "message %d", i)
+					logger.Warning(context.Background(), "message %d", i)
+					logger.InfoKV(context.Background(), "other message", "attempt", i)
+				}
+			},
+			expectedLogs: []string{"message 1", "message 1", "other message", "message 2", "message 2", "other message"},
+		},
+		{
+			desc: "should reset the counters after the interval",
+			log: func(logger *Logger, clock *time.Time) {
+				for i := 1; i <= 3; i++ {
+					logger.Info(context.Background(), "message %d", i)
+				}
+				*clock = clock.Add(time.Second)
+				logger.Info(context.Background(), "message %d", 4)
+			},
+			expectedLogs: []string{"message 1", "message 2", "message 4"},
+		},
+		{
+			desc: "should sample errors by code",
+			log: func(logger *Logger, clock *time.Time) {
+				for i := 1; i <= 3; i++ {
+					logger.Error(context.Background(), ditoErrors.New("error %d", i).WithCode("RANDOM_ERROR"))
+				}
+			},
+			expectedLogs: []string{"error 1", "error 2"},
+		},
+		{
+			desc: "should sample uncoded errors by message",
+			log: func(logger *Logger, clock *time.Time) {
+				for i := 1; i <= 3; i++ {
+					logger.Error(context.Background(), ditoErrors.New("database is down"))
+					logger.Error(context.Background(), ditoErrors.New("queue is full"))
+				}
+			},
+			expectedLogs: []string{"database is down", "queue is full", "database is down", "queue is full"},
+		},
+		{
+			desc: "should always write errors of sampled traces",
+			log: func(logger *Logger, clock *time.Time) {
+				for i := 1; i <= 3; i++ {
+					logger.Error(sampledCtx, ditoErrors.New("error %d", i))
+					logger.Info(sampledCtx, "message %d", i)
+				}
+			},
+			expectedLogs: []string{"error 1", "message 1", "error 2", "message 2", "error 3"},
+		},
+		{
+			desc: "should report the dropped logs on flush",
+			log: func(logger *Logger, clock *time.Time) {
+				for i := 1; i <= 4; i++ {
+					logger.Info(context.Background(), "message %d", i)
+				}
+				logger.Flush()
+			},
+			expectedLogs: []string{"message 1", "message 2", "log sampling dropped 2 INFO logs"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLogger(LoggerInput{
+				Level:    "DEBUG",
+				Writer:   &buf,
+				Encoder:  messageEncoder{},
+				Sampling: &SamplingInput{Initial: 2, Thereafter: 3, Interval: time.Second, ReportInterval: time.Minute},
+			})
+			defer logger.Close()
+
+			clock := mockedTimmer()()
+			logger.now = func() time.Time { return clock }
+
+			tc.log(logger, &clock)
+
+			if diff := cmp.Diff(tc.expectedLogs, strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSamplingReport(t *testing.T) {
+	t.Run("should report the dropped logs periodically, even when no other log is written", func(t *testing.T) {
+		buf := &syncBuffer{}
+		logger := NewLogger(LoggerInput{
+			Writer:   buf,
+			Encoder:  messageEncoder{},
+			Sampling: &SamplingInput{Initial: 1, Interval: time.Minute, ReportInterval: 10 * time.Millisecond},
+		})
+		defer logger.Close()
+
+		for i := 1; i <= 3; i++ {
+			logger.Info(context.Background(), "message %d", i)
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for !strings.Contains(buf.String(), "dropped") && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		expectedLogs := []string{"message 1", "log sampling dropped 2 INFO logs"}
+		if diff := cmp.Diff(expectedLogs, strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")); diff != "" {
+			t.Errorf("mismatch (-want, +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should not record span events of dropped logs", func(t *testing.T) {
+		logger := NewLogger(LoggerInput{
+			Writer:   &bytes.Buffer{},
+			Encoder:  messageEncoder{},
+			Redactor: NewRedactor(RedactorInput{}),
+			Sampling: &SamplingInput{Initial: 1, Interval: time.Minute},
+		})
+		defer logger.Close()
+
+		span := &eventRecorder{Span: trace.SpanFromContext(context.Background())}
+		ctx := context.WithValue(context.Background(), ContextKeySpan, span)
+		for i := 1; i <= 3; i++ {
+			logger.Info(ctx, "message %d", i)
+			logger.Error(ctx, ditoErrors.New("error %d", i).WithCode("RANDOM_ERROR"))
+		}
+
+		if len(span.events) != 2 {
+			t.Errorf("expected 2 span events, got %d: %v", len(span.events), span.events)
+		}
+	})
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mux sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	return b.buf.String()
+}
+
+// messageEncoder encodes only the message of Entries.
+type messageEncoder struct{}
+
+func (messageEncoder) Encode(entry Entry) ([]byte, error) {
+	return []byte(entry.Message + "\n"), nil
+}
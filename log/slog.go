@@ -0,0 +1,134 @@
+//go:build go1.21
+
+package log
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SlogLevelCritical is the slog.Level logged with the CRITICAL severity, since slog has no equivalent level.
+const SlogLevelCritical = slog.LevelError + 4
+
+// SlogHandler is a slog.Handler backed by a Logger, so logs written through log/slog have the same level threshold,
+// context attributes, Encoder and Writer of the Logger, and are correlated with the active span just like the
+// Logger ones.
+type SlogHandler struct {
+	logger Logger
+	group  string
+}
+
+// NewSlogHandler creates a new SlogHandler that writes its logs through the given Logger.
+func NewSlogHandler(logger *Logger) *SlogHandler {
+	return &SlogHandler{logger: *logger}
+}
+
+// Enabled reports whether the Logger level, or the one overriding it for ctx, allows logs of the given slog.Level.
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.logger.enabled(ctx, levelFromSlog(level))
+}
+
+// Handle writes the slog.Record, and records it as an event of the span found in ctx.
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	span := spanFromContext(ctx)
+
+	attrs := h.logger.extractLogAttributesFromContext(ctx)
+	for key, value := range h.logger.fields {
+		attrs[key] = value
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addSlogAttr(attrs, h.group, attr)
+		return true
+	})
+	msg := h.logger.redact(record.Message, attrs)
+
+	level := levelFromSlog(record.Level)
+	if !h.logger.sample(level, record.Message, span.SpanContext()) {
+		return nil
+	}
+
+	span.AddEvent("log", trace.WithAttributes(buildOtelAttributes(attrs, "log")...))
+
+	t := record.Time
+	if t.IsZero() {
+		t = h.logger.now()
+	}
+
+	h.logger.write(Entry{
+		Time:        t,
+		Level:       level,
+		Message:     msg,
+		Attributes:  attrs,
+		SpanContext: span.SpanContext(),
+		PC:          record.PC,
+		HTTPRequest: h.logger.httpRequest(ctx),
+	})
+
+	return nil
+}
+
+// WithAttrs returns a copy of the SlogHandler that includes the given attributes in every log.
+func (h *SlogHandler) WithAttrs(slogAttrs []slog.Attr) slog.Handler {
+	fields := make(map[LogAttribute]interface{}, len(h.logger.fields)+len(slogAttrs))
+	for key, value := range h.logger.fields {
+		fields[key] = value
+	}
+	for _, attr := range slogAttrs {
+		addSlogAttr(fields, h.group, attr)
+	}
+
+	child := *h
+	child.logger.fields = fields
+	return &child
+}
+
+// WithGroup returns a copy of the SlogHandler that prefixes the keys of the attributes added later with name,
+// joined by a dot.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	child := *h
+	child.group = h.group + name + "."
+	return &child
+}
+
+// addSlogAttr adds the attribute to attrs, flattening groups into dotted keys.
+func addSlogAttr(attrs map[LogAttribute]interface{}, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix = prefix + attr.Key + "."
+		}
+
+		for _, groupAttr := range value.Group() {
+			addSlogAttr(attrs, groupPrefix, groupAttr)
+		}
+		return
+	}
+
+	if attr.Key == "" {
+		return
+	}
+
+	attrs[LogAttribute(prefix+attr.Key)] = value.Any()
+}
+
+func levelFromSlog(level slog.Level) Level {
+	switch {
+	case level >= SlogLevelCritical:
+		return LevelCritical
+	case level >= slog.LevelError:
+		return LevelError
+	case level >= slog.LevelWarn:
+		return LevelWarning
+	case level >= slog.LevelInfo:
+		return LevelInfo
+	default:
+		return LevelDebug
+	}
+}
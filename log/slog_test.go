@@ -0,0 +1,99 @@
+//go:build go1.21
+
+package log
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSlogHandler(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	span := trace.SpanFromContext(trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})))
+
+	ctx := context.WithValue(context.WithValue(context.Background(), ContextKeySpan, span), "brand", "dito")
+
+	tt := []struct {
+		desc        string
+		level       string
+		log         func(logger *slog.Logger)
+		expectedLog string
+	}{
+		{
+			desc:  "should produce the same logs of Logger",
+			level: "DEBUG",
+			log: func(logger *slog.Logger) {
+				logger.InfoContext(ctx, "random message", "attempt", 1)
+			},
+			expectedLog: `{"logging.googleapis.com/trace":"projects/new-dito/traces/4bf92f3577b34da6a3ce929d0e0e4736","logging.googleapis.com/spanId":"00f067aa0ba902b7","logging.googleapis.com/trace_sampled":true,"time":"2020-12-01T12:00:00Z","severity":"INFO","message":"random message","attributes":{"attempt":1,"brand":"dito"}}`,
+		},
+		{
+			desc:  "should flatten groups into dotted keys",
+			level: "DEBUG",
+			log: func(logger *slog.Logger) {
+				logger.With("service", "api").WithGroup("request").WarnContext(context.Background(), "random message", "method", "GET", slog.Group("user", "id", 7))
+			},
+			expectedLog: `{"time":"2020-12-01T12:00:00Z","severity":"WARNING","message":"random message","attributes":{"request.method":"GET","request.user.id":7,"service":"api"}}`,
+		},
+		{
+			desc:  "should map slog levels into severities",
+			level: "DEBUG",
+			log: func(logger *slog.Logger) {
+				logger.Log(context.Background(), SlogLevelCritical, "random message")
+			},
+			expectedLog: `{"time":"2020-12-01T12:00:00Z","severity":"CRITICAL","message":"random message"}`,
+		},
+		{
+			desc:  "should respect the Logger level",
+			level: "WARNING",
+			log: func(logger *slog.Logger) {
+				logger.InfoContext(ctx, "random message")
+			},
+			expectedLog: "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			logger := NewLogger(LoggerInput{Level: tc.level, Attributes: LogAttributeSet{"brand": true}})
+			logger.now = mockedTimmer()
+
+			out := captureOutput(func() {
+				handler := NewSlogHandler(logger)
+				tc.log(slog.New(timeless{handler}))
+			})
+
+			if diff := cmp.Diff(tc.expectedLog, out); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// timeless clears the time of records, so the mocked time of the Logger is used instead.
+type timeless struct {
+	slog.Handler
+}
+
+func (h timeless) Handle(ctx context.Context, record slog.Record) error {
+	record.Time = time.Time{}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h timeless) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return timeless{h.Handler.WithAttrs(attrs)}
+}
+
+func (h timeless) WithGroup(name string) slog.Handler {
+	return timeless{h.Handler.WithGroup(name)}
+}
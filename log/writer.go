@@ -0,0 +1,130 @@
+package log
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+// stdoutWriter writes to the current os.Stdout, resolved at each write, so it keeps working if os.Stdout is replaced.
+type stdoutWriter struct{}
+
+func (w stdoutWriter) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
+
+// Flusher is implemented by Writers that buffer data, which must be flushed before the application exits.
+type Flusher interface {
+	Flush() error
+}
+
+// ErrWriterClosed is returned when writing to an AsyncWriter that was already closed.
+var ErrWriterClosed = errors.New("log writer is closed")
+
+// AsyncWriter is an io.Writer that hands writes over to a background goroutine, so logging doesn't block on IO.
+// Writes only block when its buffer is full. Flush, or Close, must be called before the application exits,
+// otherwise buffered data is lost.
+type AsyncWriter struct {
+	w       io.Writer
+	entries chan asyncEntry
+
+	mux    sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+// asyncEntry is either some data to be written, or a flush request, which is acknowledged by closing flushed.
+type asyncEntry struct {
+	data    []byte
+	flushed chan struct{}
+}
+
+// NewAsyncWriter creates a new AsyncWriter, which buffers up to bufferSize writes before writing them to w.
+func NewAsyncWriter(w io.Writer, bufferSize int) *AsyncWriter {
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+
+	aw := &AsyncWriter{
+		w:       w,
+		entries: make(chan asyncEntry, bufferSize),
+		done:    make(chan struct{}),
+	}
+
+	go aw.run()
+
+	return aw
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+
+	for entry := range w.entries {
+		if entry.flushed != nil {
+			close(entry.flushed)
+			continue
+		}
+
+		w.w.Write(entry.data)
+	}
+}
+
+// Write buffers a copy of p to be written in background. Since the actual write happens later,
+// its errors aren't reported.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	w.mux.RLock()
+	defer w.mux.RUnlock()
+
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	data := make([]byte, len(p))
+	copy(data, p)
+	w.entries <- asyncEntry{data: data}
+
+	return len(p), nil
+}
+
+// Flush blocks until every buffered write is done, then flushes the underlying writer, if it's a Flusher.
+func (w *AsyncWriter) Flush() error {
+	w.mux.RLock()
+	if w.closed {
+		w.mux.RUnlock()
+		return nil
+	}
+
+	flushed := make(chan struct{})
+	w.entries <- asyncEntry{flushed: flushed}
+	w.mux.RUnlock()
+
+	<-flushed
+
+	if f, ok := w.w.(Flusher); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
+
+// Close flushes the AsyncWriter and stops its background goroutine. Further writes fail with ErrWriterClosed.
+func (w *AsyncWriter) Close() error {
+	w.mux.Lock()
+	if w.closed {
+		w.mux.Unlock()
+		return nil
+	}
+
+	w.closed = true
+	close(w.entries)
+	w.mux.Unlock()
+
+	<-w.done
+
+	if f, ok := w.w.(Flusher); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+type flushRecorder struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (f *flushRecorder) Flush() error {
+	f.flushes++
+	return nil
+}
+
+func TestAsyncWriter(t *testing.T) {
+	t.Run("should write every buffered entry on Flush", func(t *testing.T) {
+		var out flushRecorder
+		w := NewAsyncWriter(&out, 2)
+		defer w.Close()
+
+		logger := NewLogger(LoggerInput{Level: "DEBUG", Writer: w, Encoder: LogfmtEncoder{}})
+		logger.now = mockedTimmer()
+
+		for i := 0; i < 10; i++ {
+			logger.Info(context.Background(), "message %d", i)
+		}
+
+		if err := logger.Flush(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if lines := strings.Count(out.String(), "\n"); lines != 10 {
+			t.Errorf("expected 10 lines, got %d", lines)
+		}
+
+		if out.flushes != 1 {
+			t.Errorf("expected the underlying writer to be flushed once, got %d", out.flushes)
+		}
+	})
+
+	t.Run("should refuse writes after Close", func(t *testing.T) {
+		var out bytes.Buffer
+		w := NewAsyncWriter(&out, 2)
+
+		w.Write([]byte("first\n"))
+		if err := w.Close(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if out.String() != "first\n" {
+			t.Errorf("expected buffered data to be written on Close, got %q", out.String())
+		}
+
+		if _, err := w.Write([]byte("second\n")); err != ErrWriterClosed {
+			t.Errorf("expected ErrWriterClosed, got %v", err)
+		}
+	})
+}
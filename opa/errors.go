@@ -12,6 +12,7 @@ const (
 	ERR_CODE_EVAL_REGO_FAILURE         = "OPA_EVAL_REGO_FAILURE"
 	ERR_CODE_NO_DECISION               = "OPA_NO_DECISION"
 	ERR_CODE_NON_BOOLEAN_DECISION      = "OPA_NON_BOOLEAN_DECISION"
+	ERR_CODE_BUNDLE_NOT_ACTIVATED      = "OPA_BUNDLE_NOT_ACTIVATED"
 )
 
 var (
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/open-policy-agent/opa/plugins"
+	"github.com/open-policy-agent/opa/plugins/bundle"
 	"github.com/open-policy-agent/opa/plugins/discovery"
 	"github.com/open-policy-agent/opa/rego"
 	"github.com/open-policy-agent/opa/storage"
@@ -26,17 +27,17 @@ type Client struct {
 func NewClient(opaBundleBaseURL string, opaPollingMinDelay, opaPollingMaxDelay int) (Client, error) {
 	manager, err := plugins.New(buildOPAConfigFile(opaBundleBaseURL, opaPollingMinDelay, opaPollingMaxDelay), uuid.New().String(), inmem.New())
 	if err != nil {
-		return Client{}, errors.New(err.Error()).WithKind(errors.KindInvalidInput).WithCode(ERR_CODE_BUILD_MANAGER_FAILURE)
+		return Client{}, errors.Wrap(err, "").WithKind(errors.KindInvalidInput).WithCode(ERR_CODE_BUILD_MANAGER_FAILURE)
 	}
 
 	d, err := discovery.New(manager)
 	if err != nil {
-		return Client{}, errors.New(err.Error()).WithCode(ERR_CODE_BUILD_DISCOVERY_FAILURE)
+		return Client{}, errors.Wrap(err, "").WithCode(ERR_CODE_BUILD_DISCOVERY_FAILURE)
 	}
 
 	manager.Register("discovery", d)
 	if err = manager.Start(context.Background()); err != nil {
-		return Client{}, errors.New(err.Error()).WithCode(ERR_CODE_START_MANAGER_FAILURE)
+		return Client{}, errors.Wrap(err, "").WithCode(ERR_CODE_START_MANAGER_FAILURE)
 	}
 
 	return Client{manager: manager}, nil
@@ -75,12 +76,12 @@ func (c Client) DecideIfAllowed(ctx context.Context, regoQuery, method, path, br
 			rego.Transaction(txn),
 		).PrepareForEval(ctx)
 		if err != nil {
-			return errors.New(err.Error()).WithCode(ERR_CODE_BUILD_REGO_OBJECT_FAILURE)
+			return errors.Wrap(err, "").WithCode(ERR_CODE_BUILD_REGO_OBJECT_FAILURE)
 		}
 
 		rs, err := q.Eval(ctx)
 		if err != nil {
-			return errors.New(err.Error()).WithCode(ERR_CODE_EVAL_REGO_FAILURE)
+			return errors.Wrap(err, "").WithCode(ERR_CODE_EVAL_REGO_FAILURE)
 		}
 		if len(rs) == 0 {
 			return ErrNoDecision
@@ -112,11 +113,11 @@ func (c Client) ExecuteQuery(ctx context.Context, query string, input map[string
 			rego.Transaction(txn),
 		).PrepareForEval(ctx)
 		if err != nil {
-			return errors.New(err.Error()).WithCode(ERR_CODE_BUILD_REGO_OBJECT_FAILURE)
+			return errors.Wrap(err, "").WithCode(ERR_CODE_BUILD_REGO_OBJECT_FAILURE)
 		}
 
 		if regoResult, err = q.Eval(ctx); err != nil {
-			return errors.New(err.Error()).WithCode(ERR_CODE_EVAL_REGO_FAILURE)
+			return errors.Wrap(err, "").WithCode(ERR_CODE_EVAL_REGO_FAILURE)
 		}
 
 		return nil
@@ -128,6 +129,20 @@ func (c Client) ExecuteQuery(ctx context.Context, query string, input map[string
 	return queryResult, err
 }
 
+// HealthCheck reports whether the authorization bundle was already downloaded and activated.
+func (c Client) HealthCheck(ctx context.Context) error {
+	status, ok := c.manager.PluginStatus()[bundle.Name]
+	if !ok || status == nil {
+		return errors.New("bundle plugin is not registered").WithKind(errors.KindInternal).WithCode(ERR_CODE_BUNDLE_NOT_ACTIVATED)
+	}
+
+	if status.State != plugins.StateOK {
+		return errors.New("bundle plugin is in %s state: %s", status.State, status.Message).WithKind(errors.KindInternal).WithCode(ERR_CODE_BUNDLE_NOT_ACTIVATED)
+	}
+
+	return nil
+}
+
 func buildOPAConfigFile(opaBundleBaseURL string, opaPollingMinDelay, opaPollingMaxDelay int) []byte {
 	configFile := fmt.Sprintf(`---
 services:
@@ -18,6 +18,12 @@ type Getter interface {
 	Get(ctx context.Context) (serverID string, err error)
 }
 
+// Exister defines boundary interfaces of pubsub resources, like topics and subscriptions,
+// which are able to check their own existence.
+type Exister interface {
+	Exists(ctx context.Context) (bool, error)
+}
+
 // ToByteser defines the interface of pubsub client types.
 type ToByteser interface {
 	ToBytes() ([]byte, error)
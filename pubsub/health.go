@@ -0,0 +1,50 @@
+package pubsub
+
+import (
+	"context"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+// CodeResourceNotFound indicates that the checked pubsub resource does not exist.
+const CodeResourceNotFound errors.CodeType = "PUBSUB_RESOURCE_NOT_FOUND"
+
+// ConnectivityChecker checks the connectivity with pubsub by verifying that a topic or a subscription exists.
+// It implements the go-dito http.HealthChecker contract.
+type ConnectivityChecker struct {
+	resource Exister
+}
+
+// NewConnectivityChecker returns a new instance of ConnectivityChecker.
+func NewConnectivityChecker(resource Exister) (ConnectivityChecker, error) {
+	if resource == nil {
+		return ConnectivityChecker{}, errors.NewMissingRequiredDependency("resource")
+	}
+
+	return ConnectivityChecker{resource: resource}, nil
+}
+
+// MustNewConnectivityChecker initializes ConnectivityChecker by calling NewConnectivityChecker.
+// It panics if any error is found.
+func MustNewConnectivityChecker(resource Exister) ConnectivityChecker {
+	c, err := NewConnectivityChecker(resource)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// HealthCheck reaches pubsub to verify whether the resource exists.
+func (c ConnectivityChecker) HealthCheck(ctx context.Context) error {
+	exists, err := c.resource.Exists(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return errors.New("pubsub resource does not exist").WithKind(errors.KindNotFound).WithCode(CodeResourceNotFound)
+	}
+
+	return nil
+}
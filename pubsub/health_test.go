@@ -0,0 +1,57 @@
+package pubsub_test
+
+import (
+	"github.com/ditointernet/go-dito/errors"
+	"github.com/ditointernet/go-dito/pubsub"
+	"github.com/ditointernet/go-dito/pubsub/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConnectivityChecker", func() {
+	Context("NewConnectivityChecker", func() {
+		When("resource dependency is missing", func() {
+			It("returns a MissingRequiredDependency error", func() {
+				_, err := pubsub.NewConnectivityChecker(nil)
+				Expect(err).To(Equal(errors.NewMissingRequiredDependency("resource")))
+			})
+		})
+	})
+
+	Context("HealthCheck", func() {
+		var (
+			existerM *mocks.MockExister
+			checker  pubsub.ConnectivityChecker
+		)
+
+		BeforeEach(func() {
+			existerM = mocks.NewMockExister(ctrl)
+			checker = pubsub.MustNewConnectivityChecker(existerM)
+		})
+
+		When("pubsub can't be reached", func() {
+			It("returns the error", func() {
+				existerM.EXPECT().Exists(ctx).Return(false, errors.New("connection refused"))
+				Expect(checker.HealthCheck(ctx)).To(Equal(errors.New("connection refused")))
+			})
+		})
+
+		When("the resource does not exist", func() {
+			It("returns a not found error", func() {
+				existerM.EXPECT().Exists(ctx).Return(false, nil)
+
+				err := checker.HealthCheck(ctx)
+				Expect(errors.Kind(err)).To(Equal(errors.KindNotFound))
+				Expect(errors.Code(err)).To(Equal(pubsub.CodeResourceNotFound))
+			})
+		})
+
+		When("the resource exists", func() {
+			It("returns no error", func() {
+				existerM.EXPECT().Exists(ctx).Return(true, nil)
+				Expect(checker.HealthCheck(ctx)).To(BeNil())
+			})
+		})
+	})
+})
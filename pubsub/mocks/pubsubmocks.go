@@ -89,6 +89,44 @@ func (mr *MockGetterMockRecorder) Get(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockGetter)(nil).Get), ctx)
 }
 
+// MockExister is a mock of Exister interface.
+type MockExister struct {
+	ctrl     *gomock.Controller
+	recorder *MockExisterMockRecorder
+}
+
+// MockExisterMockRecorder is the mock recorder for MockExister.
+type MockExisterMockRecorder struct {
+	mock *MockExister
+}
+
+// NewMockExister creates a new mock instance.
+func NewMockExister(ctrl *gomock.Controller) *MockExister {
+	mock := &MockExister{ctrl: ctrl}
+	mock.recorder = &MockExisterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExister) EXPECT() *MockExisterMockRecorder {
+	return m.recorder
+}
+
+// Exists mocks base method.
+func (m *MockExister) Exists(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockExisterMockRecorder) Exists(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockExister)(nil).Exists), ctx)
+}
+
 // MockToByteser is a mock of ToByteser interface.
 type MockToByteser struct {
 	ctrl     *gomock.Controller
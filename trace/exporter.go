@@ -0,0 +1,133 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	gcpexporter "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/trace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Exporter names a destination of the spans, or of the metrics
+type Exporter string
+
+const (
+	// ExporterGCP exports spans to Google Cloud Trace, and metrics to Google Cloud Monitoring
+	ExporterGCP Exporter = "gcp"
+	// ExporterOTLPGRPC exports spans, or metrics, to an OTLP collector over gRPC
+	ExporterOTLPGRPC Exporter = "otlpgrpc"
+	// ExporterOTLPHTTP exports spans, or metrics, to an OTLP collector over HTTP
+	ExporterOTLPHTTP Exporter = "otlphttp"
+	// ExporterStdout pretty prints spans, or metrics, which is suited for local development
+	ExporterStdout Exporter = "stdout"
+	// ExporterPrometheus serves metrics to be pulled by Prometheus. It's only supported by the MeterProvider
+	ExporterPrometheus Exporter = "prometheus"
+	// ExporterNone disables the export of spans, or metrics
+	ExporterNone Exporter = "none"
+)
+
+// exportersFromEnv reads the exporters from a standard environment variable, like OTEL_TRACES_EXPORTER,
+// holding a comma separated list of otlp, console, gcp, prometheus or none. The protocol of otlp is read from
+// the protocolEnvVar, like OTEL_EXPORTER_OTLP_TRACES_PROTOCOL, or from OTEL_EXPORTER_OTLP_PROTOCOL,
+// and defaults to http/protobuf.
+func exportersFromEnv(envVar, protocolEnvVar string) ([]Exporter, error) {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return nil, nil
+	}
+
+	var exporters []Exporter
+	for _, name := range strings.Split(value, ",") {
+		switch strings.TrimSpace(name) {
+		case "otlp":
+			exporters = append(exporters, otlpExporterFromEnv(protocolEnvVar))
+		case "console", "stdout":
+			exporters = append(exporters, ExporterStdout)
+		case "gcp":
+			exporters = append(exporters, ExporterGCP)
+		case "prometheus":
+			exporters = append(exporters, ExporterPrometheus)
+		case "none":
+			return []Exporter{ExporterNone}, nil
+		default:
+			return nil, fmt.Errorf("unsupported %s %q", envVar, name)
+		}
+	}
+
+	return exporters, nil
+}
+
+func otlpExporterFromEnv(protocolEnvVar string) Exporter {
+	protocol := os.Getenv(protocolEnvVar)
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+
+	if protocol == "grpc" {
+		return ExporterOTLPGRPC
+	}
+
+	return ExporterOTLPHTTP
+}
+
+// exporters returns the exporters chosen in Params, then in the environment. When none is chosen, spans are exported
+// to Google Cloud Trace in production environments only.
+func (p Params) exporters() ([]Exporter, error) {
+	if len(p.Exporters) > 0 {
+		return p.Exporters, nil
+	}
+
+	exporters, err := exportersFromEnv("OTEL_TRACES_EXPORTER", "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if err != nil || len(exporters) > 0 {
+		return exporters, err
+	}
+
+	if p.IsProductionEnvironment {
+		return []Exporter{ExporterGCP}, nil
+	}
+
+	return nil, nil
+}
+
+func newSpanExporter(ctx context.Context, exporter Exporter, params Params) (sdktrace.SpanExporter, error) {
+	switch exporter {
+	case ExporterGCP:
+		// Defaults to application credential's ProjectID if param is empty.
+		return gcpexporter.New(gcpexporter.WithProjectID(params.ProjectID))
+	case ExporterOTLPGRPC:
+		opts := []otlptracegrpc.Option{}
+		if params.OTLPEndpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(params.OTLPEndpoint))
+		}
+		if params.OTLPInsecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		return otlptracegrpc.New(ctx, opts...)
+	case ExporterOTLPHTTP:
+		opts := []otlptracehttp.Option{}
+		if params.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(params.OTLPEndpoint))
+		}
+		if params.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		return otlptracehttp.New(ctx, opts...)
+	case ExporterStdout:
+		var w io.Writer = os.Stdout
+		if params.StdoutWriter != nil {
+			w = params.StdoutWriter
+		}
+
+		return stdouttrace.New(stdouttrace.WithWriter(w), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unsupported trace exporter %q", exporter)
+	}
+}
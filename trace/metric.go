@@ -0,0 +1,239 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	gcpmetricexporter "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/metric"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	otrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+// DefaultMetricInterval is how often metrics are pushed to the exporters, besides the Prometheus one
+const DefaultMetricInterval = time.Minute
+
+// NewMeterProvider creates a new MeterProvider, described by the same Resource of the Tracer, and sets it as the
+// global one, so the http, pubsub and opa packages emit metrics through it. Metrics are exported to the
+// MetricExporters of Params, and Go runtime metrics are recorded unless DisableRuntimeMetrics is set.
+// The returned function flushes and stops the MeterProvider, and should be called before the application exits.
+func NewMeterProvider(params Params) (metric.MeterProvider, func(context.Context) error, error) {
+	if params.ApplicationName == "" {
+		return nil, nil, errors.NewMissingRequiredDependency("ApplicationName")
+	}
+
+	res, err := NewResource(context.Background(), params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mp, err := newMeterProvider(params, res)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mp, mp.Shutdown, nil
+}
+
+// MustNewMeterProvider creates a new MeterProvider. It panics if any error is found during its construction.
+func MustNewMeterProvider(params Params) (metric.MeterProvider, func(context.Context) error) {
+	mp, shutdown, err := NewMeterProvider(params)
+	if err != nil {
+		panic(err)
+	}
+
+	return mp, shutdown
+}
+
+func newMeterProvider(params Params, res *resource.Resource) (*sdkmetric.MeterProvider, error) {
+	mOpts := []sdkmetric.Option{
+		sdkmetric.WithResource(res),
+	}
+
+	exporters, err := params.metricExporters()
+	if err != nil {
+		return nil, err
+	}
+
+	interval := params.MetricInterval
+	if interval <= 0 {
+		interval = DefaultMetricInterval
+	}
+
+	var readers []sdkmetric.Reader
+	for _, exporter := range exporters {
+		if exporter == ExporterNone {
+			continue
+		}
+
+		reader, err := newMetricReader(context.Background(), exporter, params, interval)
+		if err != nil {
+			// The readers created so far hold connections and goroutines no MeterProvider will ever release.
+			for _, reader := range readers {
+				reader.Shutdown(context.Background())
+			}
+			return nil, err
+		}
+		readers = append(readers, reader)
+
+		mOpts = append(mOpts, sdkmetric.WithReader(reader))
+	}
+
+	if params.MetricReader != nil {
+		mOpts = append(mOpts, sdkmetric.WithReader(params.MetricReader))
+	}
+
+	mp := sdkmetric.NewMeterProvider(mOpts...)
+
+	if !params.DisableRuntimeMetrics {
+		if err := runtime.Start(runtime.WithMeterProvider(mp)); err != nil {
+			mp.Shutdown(context.Background())
+			return nil, err
+		}
+	}
+
+	otel.SetMeterProvider(mp)
+
+	return mp, nil
+}
+
+// metricExporters returns the exporters chosen in Params, then in the OTEL_METRICS_EXPORTER environment variable.
+// When none is chosen, metrics are exported to Google Cloud Monitoring in production environments only.
+func (p Params) metricExporters() ([]Exporter, error) {
+	if len(p.MetricExporters) > 0 {
+		return p.MetricExporters, nil
+	}
+
+	exporters, err := exportersFromEnv("OTEL_METRICS_EXPORTER", "OTEL_EXPORTER_OTLP_METRICS_PROTOCOL")
+	if err != nil || len(exporters) > 0 {
+		return exporters, err
+	}
+
+	if p.IsProductionEnvironment {
+		return []Exporter{ExporterGCP}, nil
+	}
+
+	return nil, nil
+}
+
+func newMetricReader(ctx context.Context, exporter Exporter, params Params, interval time.Duration) (sdkmetric.Reader, error) {
+	var metricExporter sdkmetric.Exporter
+	var err error
+
+	switch exporter {
+	case ExporterGCP:
+		// Defaults to application credential's ProjectID if param is empty.
+		metricExporter, err = gcpmetricexporter.New(gcpmetricexporter.WithProjectID(params.ProjectID))
+	case ExporterOTLPGRPC:
+		opts := []otlpmetricgrpc.Option{}
+		if params.OTLPEndpoint != "" {
+			opts = append(opts, otlpmetricgrpc.WithEndpoint(params.OTLPEndpoint))
+		}
+		if params.OTLPInsecure {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+
+		metricExporter, err = otlpmetricgrpc.New(ctx, opts...)
+	case ExporterOTLPHTTP:
+		opts := []otlpmetrichttp.Option{}
+		if params.OTLPEndpoint != "" {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(params.OTLPEndpoint))
+		}
+		if params.OTLPInsecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+
+		metricExporter, err = otlpmetrichttp.New(ctx, opts...)
+	case ExporterStdout:
+		var w io.Writer = os.Stdout
+		if params.StdoutWriter != nil {
+			w = params.StdoutWriter
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "\t")
+
+		metricExporter, err = stdoutmetric.New(stdoutmetric.WithEncoder(encoder))
+	case ExporterPrometheus:
+		registerer := params.PrometheusRegisterer
+		if registerer == nil {
+			registerer = prometheus.DefaultRegisterer
+		}
+
+		// The Prometheus exporter is a Reader itself, since metrics are pulled.
+		return otelprometheus.New(otelprometheus.WithRegisterer(registerer))
+	default:
+		return nil, fmt.Errorf("unsupported metric exporter %q", exporter)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(interval)), nil
+}
+
+// Telemetry is the bootstrapped tracing and metrics of an application
+type Telemetry struct {
+	Tracer        otrace.Tracer
+	MeterProvider metric.MeterProvider
+	// Shutdown flushes and stops both the TracerProvider and the MeterProvider.
+	// It should be called before the application exits.
+	Shutdown func(context.Context) error
+}
+
+// NewTelemetry sets up both tracing and metrics, described by a single Resource, as NewTracer and
+// NewMeterProvider do.
+func NewTelemetry(params Params) (Telemetry, error) {
+	if params.ApplicationName == "" {
+		return Telemetry{}, errors.NewMissingRequiredDependency("ApplicationName")
+	}
+
+	res, err := NewResource(context.Background(), params)
+	if err != nil {
+		return Telemetry{}, err
+	}
+
+	tp, err := newTracerProvider(params, res)
+	if err != nil {
+		return Telemetry{}, err
+	}
+
+	mp, err := newMeterProvider(params, res)
+	if err != nil {
+		tp.Shutdown(context.Background())
+		return Telemetry{}, err
+	}
+
+	return Telemetry{
+		Tracer:        tp.Tracer(params.ApplicationName),
+		MeterProvider: mp,
+		Shutdown: func(ctx context.Context) error {
+			return errors.NewMultiError(tp.Shutdown(ctx), mp.Shutdown(ctx)).ErrorOrNil()
+		},
+	}, nil
+}
+
+// MustNewTelemetry sets up both tracing and metrics. It panics if any error is found during their construction.
+func MustNewTelemetry(params Params) Telemetry {
+	telemetry, err := NewTelemetry(params)
+	if err != nil {
+		panic(err)
+	}
+
+	return telemetry
+}
@@ -0,0 +1,143 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestParamsMetricExporters(t *testing.T) {
+	tt := []struct {
+		desc              string
+		params            Params
+		env               map[string]string
+		expectedExporters []Exporter
+	}{
+		{
+			desc:              "should not export metrics outside of production by default",
+			params:            Params{},
+			expectedExporters: nil,
+		},
+		{
+			desc:              "should export metrics to Google Cloud Monitoring in production by default",
+			params:            Params{IsProductionEnvironment: true},
+			expectedExporters: []Exporter{ExporterGCP},
+		},
+		{
+			desc:              "should read the exporters from the environment",
+			params:            Params{IsProductionEnvironment: true},
+			env:               map[string]string{"OTEL_METRICS_EXPORTER": "prometheus,otlp", "OTEL_EXPORTER_OTLP_METRICS_PROTOCOL": "grpc"},
+			expectedExporters: []Exporter{ExporterPrometheus, ExporterOTLPGRPC},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			for _, key := range []string{"OTEL_METRICS_EXPORTER", "OTEL_EXPORTER_OTLP_METRICS_PROTOCOL", "OTEL_EXPORTER_OTLP_PROTOCOL"} {
+				t.Setenv(key, tc.env[key])
+			}
+
+			exporters, err := tc.params.metricExporters()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.expectedExporters, exporters); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewMeterProvider(t *testing.T) {
+	t.Run("should fail without ApplicationName", func(t *testing.T) {
+		if _, _, err := NewMeterProvider(Params{}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("should record metrics, including the runtime ones, described by the application Resource", func(t *testing.T) {
+		reader := sdkmetric.NewManualReader()
+		registry := prometheus.NewRegistry()
+
+		mp, shutdown, err := NewMeterProvider(Params{
+			ApplicationName:      "random-app",
+			MetricExporters:      []Exporter{ExporterPrometheus},
+			PrometheusRegisterer: registry,
+			MetricReader:         reader,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		defer shutdown(context.Background())
+
+		counter, err := mp.Meter("test").Int64Counter("random.counter")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		counter.Add(context.Background(), 1)
+
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(context.Background(), &rm); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if name, _ := rm.Resource.Set().Value("service.name"); name.AsString() != "random-app" {
+			t.Errorf("expected the service.name random-app, got %s", name.AsString())
+		}
+
+		scopes := map[string]bool{}
+		for _, sm := range rm.ScopeMetrics {
+			scopes[sm.Scope.Name] = true
+		}
+		if !scopes["test"] || !scopes["go.opentelemetry.io/contrib/instrumentation/runtime"] {
+			t.Errorf("expected application and runtime metrics, got the scopes %v", scopes)
+		}
+
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		found := false
+		for _, family := range families {
+			if family.GetName() == "random_counter_total" {
+				found = true
+			}
+		}
+		if !found {
+			t.Error("expected random.counter to be served to Prometheus")
+		}
+	})
+}
+
+func TestNewTelemetry(t *testing.T) {
+	t.Run("should flush both providers on shutdown", func(t *testing.T) {
+		reader := sdkmetric.NewManualReader()
+
+		telemetry, err := NewTelemetry(Params{
+			ApplicationName:       "random-app",
+			MetricReader:          reader,
+			DisableRuntimeMetrics: true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		_, span := telemetry.Tracer.Start(context.Background(), "random-span")
+		span.End()
+
+		if err := telemetry.Shutdown(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(context.Background(), &rm); err == nil {
+			t.Error("expected the MeterProvider to be shut down")
+		}
+	})
+}
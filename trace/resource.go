@@ -0,0 +1,164 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	ditoErrors "github.com/ditointernet/go-dito/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// Environment variables read by the environment detectors. The Kubernetes ones are usually set through the
+// Downward API, while the Cloud Run ones are set by the platform.
+const (
+	envK8SPodName       = "K8S_POD_NAME"
+	envK8SNamespaceName = "K8S_NAMESPACE_NAME"
+	envK8SNodeName      = "K8S_NODE_NAME"
+	envK8SContainerName = "K8S_CONTAINER_NAME"
+	envCloudRunService  = "K_SERVICE"
+	envCloudRunRevision = "K_REVISION"
+	envGCPProject       = "GOOGLE_CLOUD_PROJECT"
+)
+
+// NewResource creates the Resource that describes the application, shared by its tracer and meter providers,
+// so traces and metrics line up. It merges, from the lowest to the highest precedence, the host, container,
+// process and SDK attributes, the Kubernetes and Google Cloud attributes found in environment variables,
+// the given Detectors, the OTEL_RESOURCE_ATTRIBUTES and OTEL_SERVICE_NAME environment variables and the Params.
+func NewResource(ctx context.Context, params Params) (*resource.Resource, error) {
+	if params.ApplicationName == "" {
+		return nil, ditoErrors.NewMissingRequiredDependency("ApplicationName")
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(params.ApplicationName),
+		semconv.ServiceInstanceID(params.instanceID()),
+	}
+	if params.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersion(params.ServiceVersion))
+	}
+	if params.Environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironment(params.Environment))
+	}
+	attrs = append(attrs, params.ResourceAttributes...)
+
+	detectors := []resource.Detector{kubernetesDetector{}, gcpDetector{projectID: params.ProjectID}}
+
+	res, err := resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithHost(),
+		resource.WithContainer(),
+		resource.WithProcessRuntimeName(),
+		resource.WithProcessRuntimeVersion(),
+		resource.WithTelemetrySDK(),
+		resource.WithDetectors(append(detectors, params.Detectors...)...),
+		resource.WithFromEnv(),
+		resource.WithAttributes(attrs...),
+	)
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// defaultInstanceID is generated once per process, so every Resource built by it, like the ones of
+// NewTracer and NewMeterProvider, reports the same instance.
+var defaultInstanceID struct {
+	once sync.Once
+	id   string
+}
+
+// instanceID returns the InstanceID of Params, defaulting to the Kubernetes pod name, then to a random UUID
+// that is the same for the whole process.
+func (p Params) instanceID() string {
+	if p.InstanceID != "" {
+		return p.InstanceID
+	}
+
+	if pod := os.Getenv(envK8SPodName); pod != "" {
+		return pod
+	}
+
+	defaultInstanceID.once.Do(func() {
+		defaultInstanceID.id = randomUUID()
+	})
+
+	return defaultInstanceID.id
+}
+
+// randomUUID returns a version 4 UUID
+func randomUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// kubernetesDetector detects the Kubernetes pod the application runs in from environment variables
+type kubernetesDetector struct{}
+
+func (kubernetesDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	attrs := envAttributes(map[attribute.Key]string{
+		semconv.K8SPodNameKey:       envK8SPodName,
+		semconv.K8SNamespaceNameKey: envK8SNamespaceName,
+		semconv.K8SNodeNameKey:      envK8SNodeName,
+		semconv.K8SContainerNameKey: envK8SContainerName,
+	})
+	if len(attrs) == 0 {
+		return resource.Empty(), nil
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attrs...), nil
+}
+
+// gcpDetector detects the Google Cloud project and the Cloud Run service the application runs in
+// from environment variables
+type gcpDetector struct {
+	projectID string
+}
+
+func (d gcpDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	projectID := d.projectID
+	if projectID == "" {
+		projectID = os.Getenv(envGCPProject)
+	}
+
+	attrs := envAttributes(map[attribute.Key]string{
+		semconv.FaaSNameKey:    envCloudRunService,
+		semconv.FaaSVersionKey: envCloudRunRevision,
+	})
+	if len(attrs) > 0 {
+		attrs = append(attrs, semconv.CloudPlatformGCPCloudRun)
+	}
+
+	if projectID == "" && len(attrs) == 0 {
+		return resource.Empty(), nil
+	}
+
+	attrs = append(attrs, semconv.CloudProviderGCP)
+	if projectID != "" {
+		attrs = append(attrs, semconv.CloudAccountID(projectID))
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attrs...), nil
+}
+
+// envAttributes returns the attributes whose environment variables are set
+func envAttributes(envVars map[attribute.Key]string) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for key, envVar := range envVars {
+		if value := os.Getenv(envVar); value != "" {
+			attrs = append(attrs, key.String(value))
+		}
+	}
+
+	return attrs
+}
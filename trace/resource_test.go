@@ -0,0 +1,127 @@
+package trace
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+func TestNewResource(t *testing.T) {
+	clearEnv := func(t *testing.T) {
+		for _, key := range []string{"OTEL_RESOURCE_ATTRIBUTES", "OTEL_SERVICE_NAME", envK8SPodName, envK8SNamespaceName,
+			envK8SNodeName, envK8SContainerName, envCloudRunService, envCloudRunRevision, envGCPProject} {
+			t.Setenv(key, "")
+		}
+	}
+
+	t.Run("should fail without ApplicationName", func(t *testing.T) {
+		if _, err := NewResource(context.Background(), Params{}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("should describe the service with a schema URL", func(t *testing.T) {
+		clearEnv(t)
+
+		res, err := NewResource(context.Background(), Params{
+			ApplicationName:    "random-app",
+			ServiceVersion:     "v1.2.3",
+			Environment:        "staging",
+			InstanceID:         "instance-1",
+			ResourceAttributes: []attribute.KeyValue{attribute.String("team", "platform")},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if res.SchemaURL() != semconv.SchemaURL {
+			t.Errorf("expected schema URL %s, got %s", semconv.SchemaURL, res.SchemaURL())
+		}
+
+		expected := map[string]string{
+			"service.name":           "random-app",
+			"service.version":        "v1.2.3",
+			"deployment.environment": "staging",
+			"service.instance.id":    "instance-1",
+			"team":                   "platform",
+			"telemetry.sdk.language": "go",
+		}
+		if diff := cmp.Diff(expected, pick(res.Set(), expected)); diff != "" {
+			t.Errorf("mismatch (-want, +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should detect Kubernetes and Google Cloud attributes from the environment", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv(envK8SPodName, "random-app-7d9f")
+		t.Setenv(envK8SNamespaceName, "default")
+		t.Setenv(envCloudRunService, "random-app")
+		t.Setenv(envGCPProject, "random-project")
+		t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "service.version=v2,team=platform")
+
+		res, err := NewResource(context.Background(), Params{ApplicationName: "random-app", ServiceVersion: "v3"})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		expected := map[string]string{
+			"service.instance.id": "random-app-7d9f",
+			"service.version":     "v3",
+			"team":                "platform",
+			"k8s.pod.name":        "random-app-7d9f",
+			"k8s.namespace.name":  "default",
+			"faas.name":           "random-app",
+			"cloud.platform":      "gcp_cloud_run",
+			"cloud.provider":      "gcp",
+			"cloud.account.id":    "random-project",
+		}
+		if diff := cmp.Diff(expected, pick(res.Set(), expected)); diff != "" {
+			t.Errorf("mismatch (-want, +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should generate a random instance ID", func(t *testing.T) {
+		clearEnv(t)
+
+		id := Params{}.instanceID()
+		if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(id) {
+			t.Errorf("expected a random UUID, got %s", id)
+		}
+	})
+
+	t.Run("should keep the random instance ID across Resources", func(t *testing.T) {
+		clearEnv(t)
+
+		tracerRes, err := NewResource(context.Background(), Params{ApplicationName: "random-app"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		meterRes, err := NewResource(context.Background(), Params{ApplicationName: "random-app"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tracerID, _ := tracerRes.Set().Value(semconv.ServiceInstanceIDKey)
+		meterID, _ := meterRes.Set().Value(semconv.ServiceInstanceIDKey)
+		if tracerID.AsString() == "" || tracerID != meterID {
+			t.Errorf("expected the same instance ID, got %s and %s", tracerID.AsString(), meterID.AsString())
+		}
+	})
+}
+
+// pick returns the values of the given keys found in set
+func pick(set *attribute.Set, keys map[string]string) map[string]string {
+	values := map[string]string{}
+	for key := range keys {
+		if value, ok := set.Value(attribute.Key(key)); ok {
+			values[key] = value.Emit()
+		}
+	}
+
+	return values
+}
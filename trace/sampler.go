@@ -0,0 +1,175 @@
+package trace
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	otrace "go.opentelemetry.io/otel/trace"
+)
+
+// SamplingRule sets the ratio of the traces, started by spans that match it, that are sampled.
+// A span matches a rule when it has the rule SpanName, if any, and starts with the rule Attribute set to Value,
+// if any.
+type SamplingRule struct {
+	SpanName  string
+	Attribute attribute.Key
+	Value     string
+	// Ratio follows the same rules of Params TraceRatio
+	Ratio float64
+}
+
+// RouteRule returns a SamplingRule for the HTTP requests of the given route template, like /users/<id>
+func RouteRule(route string, ratio float64) SamplingRule {
+	return SamplingRule{Attribute: semconv.HTTPRouteKey, Value: route, Ratio: ratio}
+}
+
+// TargetRule returns a SamplingRule for the HTTP requests of the given target, like /healthz.
+// The query string of the requests is ignored, so /healthz?probe=1 matches /healthz too.
+func TargetRule(target string, ratio float64) SamplingRule {
+	return SamplingRule{Attribute: semconv.HTTPTargetKey, Value: target, Ratio: ratio}
+}
+
+// SubscriptionRule returns a SamplingRule for the messages received from the given Pub/Sub subscription
+func SubscriptionRule(subscription string, ratio float64) SamplingRule {
+	return SamplingRule{Attribute: semconv.MessagingSourceNameKey, Value: subscription, Ratio: ratio}
+}
+
+func (r SamplingRule) matches(p sdktrace.SamplingParameters) bool {
+	if r.SpanName != "" && r.SpanName != p.Name {
+		return false
+	}
+
+	if r.Attribute == "" {
+		return true
+	}
+
+	for _, attr := range p.Attributes {
+		if attr.Key != r.Attribute {
+			continue
+		}
+
+		value := attr.Value.Emit()
+		if attr.Key == semconv.HTTPTargetKey {
+			value = strings.SplitN(value, "?", 2)[0]
+		}
+
+		return value == r.Value
+	}
+
+	return false
+}
+
+// ruleSampler samples according to the first SamplingRule the span matches, or to the fallback Sampler
+type ruleSampler struct {
+	rules    []SamplingRule
+	samplers []sdktrace.Sampler
+	fallback sdktrace.Sampler
+}
+
+func newRuleSampler(rules []SamplingRule, fallback sdktrace.Sampler) ruleSampler {
+	samplers := make([]sdktrace.Sampler, len(rules))
+	for i, rule := range rules {
+		samplers[i] = sdktrace.TraceIDRatioBased(rule.Ratio)
+	}
+
+	return ruleSampler{rules: rules, samplers: samplers, fallback: fallback}
+}
+
+func (s ruleSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for i, rule := range s.rules {
+		if rule.matches(p) {
+			return s.samplers[i].ShouldSample(p)
+		}
+	}
+
+	return s.fallback.ShouldSample(p)
+}
+
+func (s ruleSampler) Description() string {
+	rules := make([]string, len(s.rules))
+	for i, rule := range s.rules {
+		rules[i] = fmt.Sprintf("%s{%s=%s}:%g", rule.SpanName, rule.Attribute, rule.Value, rule.Ratio)
+	}
+
+	return fmt.Sprintf("RuleSampler{%s,fallback:%s}", strings.Join(rules, ","), s.fallback.Description())
+}
+
+// recordingSampler records the spans its Sampler drops, so keepErrorsProcessor is able to export them
+// if they end with an error
+type recordingSampler struct {
+	sampler sdktrace.Sampler
+}
+
+func (s recordingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.sampler.ShouldSample(p)
+	if result.Decision == sdktrace.Drop {
+		result.Decision = sdktrace.RecordOnly
+	}
+
+	return result
+}
+
+func (s recordingSampler) Description() string {
+	return fmt.Sprintf("RecordingSampler{%s}", s.sampler.Description())
+}
+
+// keepErrorsProcessor hands the sampled spans, and the ones that end with an error, over to its SpanProcessor,
+// which only exports sampled spans
+type keepErrorsProcessor struct {
+	sdktrace.SpanProcessor
+}
+
+func (p keepErrorsProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		if s.Status().Code != codes.Error {
+			return
+		}
+
+		s = keptSpan{s}
+	}
+
+	p.SpanProcessor.OnEnd(s)
+}
+
+// keptSpan is a span that wasn't sampled, but is exported anyway
+type keptSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s keptSpan) SpanContext() otrace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
+
+// sampler returns the Sampler of the spans. Root spans are sampled according to the SamplingRules, then to the
+// TraceRatio in production environments, while the other ones follow the decision of their parent.
+func (p Params) sampler() sdktrace.Sampler {
+	root := sdktrace.AlwaysSample()
+	if p.IsProductionEnvironment {
+		root = sdktrace.TraceIDRatioBased(p.TraceRatio)
+	}
+
+	if len(p.SamplingRules) > 0 {
+		root = newRuleSampler(p.SamplingRules, root)
+	}
+
+	sampler := sdktrace.ParentBased(root)
+	if p.KeepErrors {
+		return recordingSampler{sampler: sampler}
+	}
+
+	return sampler
+}
+
+// spanProcessor wraps the SpanProcessor of an exporter, so it also exports the spans kept by KeepErrors
+func (p Params) spanProcessor(processor sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+	if p.KeepErrors {
+		return keepErrorsProcessor{SpanProcessor: processor}
+	}
+
+	return processor
+}
@@ -0,0 +1,114 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	otrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestSampling(t *testing.T) {
+	sampledParent := otrace.ContextWithRemoteSpanContext(context.Background(), otrace.NewSpanContext(otrace.SpanContextConfig{
+		TraceID:    otrace.TraceID{1},
+		SpanID:     otrace.SpanID{1},
+		TraceFlags: otrace.FlagsSampled,
+	}))
+	unsampledParent := otrace.ContextWithRemoteSpanContext(context.Background(), otrace.NewSpanContext(otrace.SpanContextConfig{
+		TraceID: otrace.TraceID{2},
+		SpanID:  otrace.SpanID{2},
+	}))
+
+	type span struct {
+		ctx   context.Context
+		name  string
+		attrs []attribute.KeyValue
+		err   bool
+	}
+
+	tt := []struct {
+		desc          string
+		params        Params
+		spans         []span
+		expectedSpans []string
+	}{
+		{
+			desc:   "should follow the sampling decision of the parent",
+			params: Params{IsProductionEnvironment: true, TraceRatio: 0},
+			spans: []span{
+				{ctx: sampledParent, name: "sampled"},
+				{ctx: unsampledParent, name: "unsampled"},
+				{ctx: context.Background(), name: "root"},
+			},
+			expectedSpans: []string{"sampled"},
+		},
+		{
+			desc: "should sample root spans according to the first matching rule",
+			params: Params{
+				IsProductionEnvironment: true,
+				TraceRatio:              1,
+				SamplingRules: []SamplingRule{
+					TargetRule("/healthz", 0),
+					RouteRule("/users/<id>", 1),
+					{SpanName: "GET /users/<id>", Ratio: 0},
+					SubscriptionRule("orders", 0),
+				},
+			},
+			spans: []span{
+				{ctx: context.Background(), name: "GET /healthz", attrs: []attribute.KeyValue{attribute.String("http.target", "/healthz")}},
+				{ctx: context.Background(), name: "GET /healthz?probe=1", attrs: []attribute.KeyValue{attribute.String("http.target", "/healthz?probe=1")}},
+				{ctx: context.Background(), name: "GET /users/<id>", attrs: []attribute.KeyValue{attribute.String("http.route", "/users/<id>")}},
+				{ctx: context.Background(), name: "orders receive", attrs: []attribute.KeyValue{attribute.String("messaging.source.name", "orders")}},
+				{ctx: context.Background(), name: "other"},
+				{ctx: sampledParent, name: "child of sampled", attrs: []attribute.KeyValue{attribute.String("http.target", "/healthz")}},
+			},
+			expectedSpans: []string{"GET /users/<id>", "other", "child of sampled"},
+		},
+		{
+			desc:   "should keep spans that end with an error",
+			params: Params{IsProductionEnvironment: true, TraceRatio: 0, KeepErrors: true},
+			spans: []span{
+				{ctx: context.Background(), name: "ok"},
+				{ctx: context.Background(), name: "failed", err: true},
+				{ctx: unsampledParent, name: "failed child", err: true},
+				{ctx: sampledParent, name: "sampled"},
+			},
+			expectedSpans: []string{"failed", "failed child", "sampled"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			provider := sdktrace.NewTracerProvider(
+				sdktrace.WithSampler(tc.params.sampler()),
+				sdktrace.WithSpanProcessor(tc.params.spanProcessor(sdktrace.NewSimpleSpanProcessor(exporter))),
+			)
+			tracer := provider.Tracer("test")
+
+			for _, s := range tc.spans {
+				_, span := tracer.Start(s.ctx, s.name, otrace.WithAttributes(s.attrs...))
+				if s.err {
+					span.SetStatus(codes.Error, "random error")
+				}
+				span.End()
+			}
+
+			var names []string
+			for _, span := range exporter.GetSpans() {
+				names = append(names, span.Name)
+				if !span.SpanContext.IsSampled() {
+					t.Errorf("expected exported span %s to be flagged as sampled", span.Name)
+				}
+			}
+
+			if diff := cmp.Diff(tc.expectedSpans, names); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
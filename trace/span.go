@@ -0,0 +1,139 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	otrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/ditointernet/go-dito/errors"
+)
+
+// instrumentationName identifies the spans started by the helpers of this package
+const instrumentationName = "github.com/ditointernet/go-dito/trace"
+
+// contextKeySpan is the string key under which integrations that can't carry typed context keys, like fasthttp
+// request contexts, store the active span. It's the same key of log.ContextKeySpan.
+const contextKeySpan = "otel_span"
+
+// Span attribute keys recorded by the helpers of this package
+const (
+	AttributeKeyErrorKind = attribute.Key("error.kind")
+	AttributeKeyErrorCode = attribute.Key("error.code")
+)
+
+// DefaultContextAttributes are the context values attached to the spans started by Start, when
+// Params ContextAttributes is nil. They're the brand and account IDs stored by the http middlewares.
+var DefaultContextAttributes = []string{"brand_id", "account_id"}
+
+var contextAttributes = struct {
+	sync.RWMutex
+	keys []string
+}{keys: DefaultContextAttributes}
+
+// setContextAttributes changes the context values attached to the spans started by Start
+func setContextAttributes(keys []string) {
+	if keys == nil {
+		keys = DefaultContextAttributes
+	}
+
+	contextAttributes.Lock()
+	defer contextAttributes.Unlock()
+
+	contextAttributes.keys = keys
+}
+
+// Start starts a span, child of the one found in ctx, using the global TracerProvider. The context values listed in
+// Params ContextAttributes are attached to it as attributes. The span must be finished with End.
+func Start(ctx context.Context, name string, opts ...otrace.SpanStartOption) (context.Context, otrace.Span) {
+	if !otrace.SpanFromContext(ctx).SpanContext().IsValid() {
+		if span, ok := ctx.Value(contextKeySpan).(otrace.Span); ok {
+			ctx = otrace.ContextWithSpan(ctx, span)
+		}
+	}
+
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+	span.SetAttributes(attributesFromContext(ctx)...)
+
+	return ctx, span
+}
+
+// End finishes the span, recording the error errp points to, if any. When called in a deferred statement,
+// it also records panics, which are propagated afterwards:
+//
+//	ctx, span := trace.Start(ctx, "process")
+//	defer trace.End(span, &err)
+func End(span otrace.Span, errp *error) {
+	if r := recover(); r != nil {
+		span.RecordError(fmt.Errorf("panic: %v", r), otrace.WithStackTrace(true))
+		span.SetStatus(codes.Error, fmt.Sprint(r))
+		span.End()
+		panic(r)
+	}
+
+	if errp != nil {
+		RecordError(span, *errp)
+	}
+
+	span.End()
+}
+
+// RecordError records err as an exception of the span, along with its kind, code and stack trace, and sets the
+// span status as an error. Nil errors are ignored.
+func RecordError(span otrace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	attrs := []attribute.KeyValue{
+		AttributeKeyErrorKind.String(string(errors.Kind(err))),
+		AttributeKeyErrorCode.String(string(errors.Code(err))),
+	}
+
+	span.SetAttributes(attrs...)
+
+	if stack := errors.StackTrace(err); stack != "" {
+		attrs = append(attrs, attribute.String("exception.stacktrace", stack))
+	}
+
+	span.RecordError(err, otrace.WithAttributes(attrs...))
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Run executes fn within a span, started by Start, that records the error fn returns and any panic it raises.
+// The span is finished once fn returns.
+func Run(ctx context.Context, name string, fn func(ctx context.Context) error, opts ...otrace.SpanStartOption) (err error) {
+	ctx, span := Start(ctx, name, opts...)
+	defer End(span, &err)
+
+	return fn(ctx)
+}
+
+func attributesFromContext(ctx context.Context) []attribute.KeyValue {
+	contextAttributes.RLock()
+	keys := contextAttributes.keys
+	contextAttributes.RUnlock()
+
+	var attrs []attribute.KeyValue
+	for _, key := range keys {
+		switch value := ctx.Value(key).(type) {
+		case nil:
+		case string:
+			attrs = append(attrs, attribute.String(key, value))
+		case int:
+			attrs = append(attrs, attribute.Int(key, value))
+		case int64:
+			attrs = append(attrs, attribute.Int64(key, value))
+		case bool:
+			attrs = append(attrs, attribute.Bool(key, value))
+		default:
+			attrs = append(attrs, attribute.String(key, fmt.Sprint(value)))
+		}
+	}
+
+	return attrs
+}
@@ -0,0 +1,145 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	otrace "go.opentelemetry.io/otel/trace"
+
+	ditoErrors "github.com/ditointernet/go-dito/errors"
+)
+
+func TestRun(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	ctx := context.WithValue(context.WithValue(context.Background(), "brand_id", "dito"), "account_id", 42)
+
+	tt := []struct {
+		desc           string
+		fn             func(ctx context.Context) error
+		expectedErr    error
+		expectedStatus codes.Code
+		expectedAttrs  map[string]string
+	}{
+		{
+			desc:           "should attach the context values",
+			fn:             func(ctx context.Context) error { return nil },
+			expectedStatus: codes.Unset,
+			expectedAttrs:  map[string]string{"brand_id": "dito", "account_id": "42"},
+		},
+		{
+			desc: "should record the kind and code of the returned error",
+			fn: func(ctx context.Context) error {
+				return ditoErrors.New("random error").WithKind(ditoErrors.KindNotFound).WithCode("RANDOM_ERROR")
+			},
+			expectedErr:    ditoErrors.New("random error").WithKind(ditoErrors.KindNotFound).WithCode("RANDOM_ERROR"),
+			expectedStatus: codes.Error,
+			expectedAttrs:  map[string]string{"brand_id": "dito", "account_id": "42", "error.kind": "NOT_FOUND", "error.code": "RANDOM_ERROR"},
+		},
+		{
+			desc:           "should record errors that aren't CustomErrors",
+			fn:             func(ctx context.Context) error { return errors.New("random error") },
+			expectedErr:    errors.New("random error"),
+			expectedStatus: codes.Error,
+			expectedAttrs:  map[string]string{"brand_id": "dito", "account_id": "42", "error.kind": "UNEXPECTED", "error.code": "UNKNOWN"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := Run(ctx, "random-span", tc.fn)
+			if diff := cmp.Diff(errString(tc.expectedErr), errString(err)); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+
+			spans := recorder.Ended()
+			span := spans[len(spans)-1]
+
+			if span.Name() != "random-span" {
+				t.Errorf("expected the span random-span, got %s", span.Name())
+			}
+
+			if span.Status().Code != tc.expectedStatus {
+				t.Errorf("expected the status %s, got %s", tc.expectedStatus, span.Status().Code)
+			}
+
+			attrs := map[string]string{}
+			for _, attr := range span.Attributes() {
+				attrs[string(attr.Key)] = attr.Value.Emit()
+			}
+			if diff := cmp.Diff(tc.expectedAttrs, attrs); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("should be a child of the span stored under the log span key", func(t *testing.T) {
+		_, parent := provider.Tracer("test").Start(context.Background(), "parent")
+		defer parent.End()
+
+		Run(context.WithValue(context.Background(), contextKeySpan, parent), "child", func(ctx context.Context) error {
+			if !otrace.SpanFromContext(ctx).SpanContext().IsValid() {
+				t.Error("expected fn to receive the span")
+			}
+			return nil
+		})
+
+		spans := recorder.Ended()
+		if got := spans[len(spans)-1].Parent().SpanID(); got != parent.SpanContext().SpanID() {
+			t.Errorf("expected the parent %s, got %s", parent.SpanContext().SpanID(), got)
+		}
+	})
+
+	t.Run("should record panics and propagate them", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != "random panic" {
+				t.Errorf("expected the panic to be propagated, got %v", r)
+			}
+
+			spans := recorder.Ended()
+			span := spans[len(spans)-1]
+
+			if span.Status().Code != codes.Error || span.Status().Description != "random panic" {
+				t.Errorf("expected an error status, got %v", span.Status())
+			}
+
+			if len(span.Events()) != 1 || !hasAttribute(span.Events()[0].Attributes, "exception.stacktrace") {
+				t.Errorf("expected the panic to be recorded with its stack trace, got %v", span.Events())
+			}
+		}()
+
+		Run(ctx, "random-span", func(ctx context.Context) error {
+			panic("random panic")
+		})
+	})
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
+func hasAttribute(attrs []attribute.KeyValue, key attribute.Key) bool {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,16 +2,19 @@ package trace
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/ditointernet/go-dito/errors"
 
-	gcpexporter "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/trace"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	otrace "go.opentelemetry.io/otel/trace"
 )
 
@@ -24,11 +27,59 @@ type Params struct {
 	// If ommited, this will default to your "Application Default Credentials".
 	ProjectID string
 
+	// ServiceVersion is the version of the application, like a release tag or a commit hash.
+	ServiceVersion string
+	// Environment is the deployment environment of the application, like staging or production.
+	Environment string
+	// InstanceID identifies the running instance of the application. Defaults to the Kubernetes pod name,
+	// or to a random UUID generated once per process, so tracers and meter providers report the same instance.
+	InstanceID string
+	// ResourceAttributes are added to the Resource that describes the application.
+	ResourceAttributes []attribute.KeyValue
+	// Detectors find further Resource attributes, like the ones of the Google Cloud platform the application
+	// runs in (go.opentelemetry.io/contrib/detectors/gcp).
+	Detectors []resource.Detector
+
 	// TraceRatio indicates how often the system should collect traces.
 	// Use it with caution: It may overload the system and also be too expensive to mantain its value too high in a
 	// high throuput system. Values vary between 0 and 1, with 0 meaning No Sampling and 1 meaning Always Sampling.
 	// Values lower than 0 are treated as 0 and values greater than 1 are treated as 1.
 	TraceRatio float64
+	// SamplingRules override the TraceRatio for the root spans that match them, the first match winning.
+	// Spans with a parent always follow its sampling decision.
+	SamplingRules []SamplingRule
+	// KeepErrors exports the spans that end with an error even when their trace isn't sampled,
+	// at the cost of recording every span.
+	KeepErrors bool
+
+	// Exporters are the destinations of the spans, all of them used at once. Defaults to the ones listed in the
+	// OTEL_TRACES_EXPORTER environment variable, or to ExporterGCP in production environments.
+	Exporters []Exporter
+	// OTLPEndpoint is the host and port of the OTLP collector. Defaults to OTEL_EXPORTER_OTLP_ENDPOINT,
+	// or to localhost.
+	OTLPEndpoint string
+	// OTLPInsecure disables TLS when connecting to the OTLP collector.
+	OTLPInsecure bool
+	// StdoutWriter is where ExporterStdout writes to. Defaults to os.Stdout.
+	StdoutWriter io.Writer
+	// SpanRecorder, when given, records every span in memory, so tests can assert on them.
+	SpanRecorder *tracetest.SpanRecorder
+	// ContextAttributes are the keys of the context values attached to the spans started by Start.
+	// Defaults to DefaultContextAttributes.
+	ContextAttributes []string
+
+	// MetricExporters are the destinations of the metrics, all of them used at once. Defaults to the ones listed in
+	// the OTEL_METRICS_EXPORTER environment variable, or to ExporterGCP in production environments.
+	MetricExporters []Exporter
+	// MetricInterval is how often metrics are pushed to the MetricExporters. Defaults to DefaultMetricInterval.
+	MetricInterval time.Duration
+	// PrometheusRegisterer is where ExporterPrometheus registers its collector. Defaults to
+	// prometheus.DefaultRegisterer.
+	PrometheusRegisterer prometheus.Registerer
+	// MetricReader, when given, also reads the metrics, e.g. a sdkmetric.ManualReader in tests.
+	MetricReader sdkmetric.Reader
+	// DisableRuntimeMetrics stops the MeterProvider from recording Go runtime metrics.
+	DisableRuntimeMetrics bool
 }
 
 // NewTracer creates a new Tracer.
@@ -40,29 +91,58 @@ func NewTracer(params Params) (otrace.Tracer, func(context.Context) error, error
 		return nil, nil, errors.NewMissingRequiredDependency("ApplicationName")
 	}
 
+	res, err := NewResource(context.Background(), params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tp, err := newTracerProvider(params, res)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tp.Tracer(params.ApplicationName), tp.Shutdown, nil
+}
+
+// newTracerProvider creates a TracerProvider that describes spans with res, then sets it, and the W3C propagators,
+// as the global ones.
+func newTracerProvider(params Params, res *resource.Resource) (*sdktrace.TracerProvider, error) {
 	tOpts := []sdktrace.TracerProviderOption{
-		sdktrace.WithResource(resource.NewSchemaless(
-			attribute.KeyValue{
-				Key:   semconv.ServiceNameKey,
-				Value: attribute.StringValue(params.ApplicationName),
-			},
-		)),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(params.sampler()),
+	}
+
+	exporters, err := params.exporters()
+	if err != nil {
+		return nil, err
 	}
 
-	if params.IsProductionEnvironment {
-		exporter, err := gcpexporter.New(
-			// Defaults to application credential's ProjectID if param is empty.
-			gcpexporter.WithProjectID(params.ProjectID),
-		)
+	var processors []sdktrace.SpanProcessor
+	for _, exporter := range exporters {
+		if exporter == ExporterNone {
+			continue
+		}
+
+		spanExporter, err := newSpanExporter(context.Background(), exporter, params)
 		if err != nil {
-			return nil, nil, err
+			// The processors created so far hold connections and goroutines the TracerProvider won't ever release.
+			for _, processor := range processors {
+				processor.Shutdown(context.Background())
+			}
+			return nil, err
 		}
 
-		tOpts = append(tOpts, sdktrace.WithSampler(sdktrace.TraceIDRatioBased(params.TraceRatio)))
-		tOpts = append(tOpts, sdktrace.WithBatcher(exporter))
+		processor := params.spanProcessor(sdktrace.NewBatchSpanProcessor(spanExporter))
+		processors = append(processors, processor)
+		tOpts = append(tOpts, sdktrace.WithSpanProcessor(processor))
+	}
+
+	if params.SpanRecorder != nil {
+		tOpts = append(tOpts, sdktrace.WithSpanProcessor(params.SpanRecorder))
 	}
 
 	tp := sdktrace.NewTracerProvider(tOpts...)
+	setContextAttributes(params.ContextAttributes)
 
 	otel.SetTracerProvider(tp)
 	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
@@ -70,7 +150,7 @@ func NewTracer(params Params) (otrace.Tracer, func(context.Context) error, error
 		propagation.Baggage{},
 	))
 
-	return tp.Tracer(params.ApplicationName), tp.Shutdown, nil
+	return tp, nil
 }
 
 // MustNewTracer creates a new Tracer.
@@ -0,0 +1,117 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestParamsExporters(t *testing.T) {
+	tt := []struct {
+		desc              string
+		params            Params
+		env               map[string]string
+		expectedExporters []Exporter
+		expectedErr       string
+	}{
+		{
+			desc:              "should not export spans outside of production by default",
+			params:            Params{},
+			expectedExporters: nil,
+		},
+		{
+			desc:              "should export spans to Google Cloud Trace in production by default",
+			params:            Params{IsProductionEnvironment: true},
+			expectedExporters: []Exporter{ExporterGCP},
+		},
+		{
+			desc:              "should prefer the exporters of Params",
+			params:            Params{IsProductionEnvironment: true, Exporters: []Exporter{ExporterStdout, ExporterOTLPGRPC}},
+			env:               map[string]string{"OTEL_TRACES_EXPORTER": "gcp"},
+			expectedExporters: []Exporter{ExporterStdout, ExporterOTLPGRPC},
+		},
+		{
+			desc:              "should read the exporters from the environment",
+			params:            Params{IsProductionEnvironment: true},
+			env:               map[string]string{"OTEL_TRACES_EXPORTER": "otlp, console"},
+			expectedExporters: []Exporter{ExporterOTLPHTTP, ExporterStdout},
+		},
+		{
+			desc:              "should read the OTLP protocol from the environment",
+			params:            Params{},
+			env:               map[string]string{"OTEL_TRACES_EXPORTER": "otlp", "OTEL_EXPORTER_OTLP_PROTOCOL": "grpc"},
+			expectedExporters: []Exporter{ExporterOTLPGRPC},
+		},
+		{
+			desc:              "should disable the export of spans",
+			params:            Params{IsProductionEnvironment: true},
+			env:               map[string]string{"OTEL_TRACES_EXPORTER": "gcp,none"},
+			expectedExporters: []Exporter{ExporterNone},
+		},
+		{
+			desc:        "should fail with unsupported exporters",
+			params:      Params{},
+			env:         map[string]string{"OTEL_TRACES_EXPORTER": "jaeger"},
+			expectedErr: `unsupported OTEL_TRACES_EXPORTER "jaeger"`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			for _, key := range []string{"OTEL_TRACES_EXPORTER", "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "OTEL_EXPORTER_OTLP_PROTOCOL"} {
+				t.Setenv(key, tc.env[key])
+			}
+
+			exporters, err := tc.params.exporters()
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.expectedExporters, exporters); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewTracerExporters(t *testing.T) {
+	t.Run("should export spans to every exporter at once", func(t *testing.T) {
+		var stdout bytes.Buffer
+		recorder := tracetest.NewSpanRecorder()
+
+		tracer, flush, err := NewTracer(Params{
+			ApplicationName: "random-app",
+			Exporters:       []Exporter{ExporterStdout},
+			StdoutWriter:    &stdout,
+			SpanRecorder:    recorder,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		_, span := tracer.Start(context.Background(), "random-span")
+		span.End()
+
+		if err := flush(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if len(recorder.Ended()) != 1 || recorder.Ended()[0].Name() != "random-span" {
+			t.Errorf("expected random-span to be recorded, got %v", recorder.Ended())
+		}
+
+		if !strings.Contains(stdout.String(), `"Name": "random-span"`) {
+			t.Errorf("expected random-span to be printed, got %s", stdout.String())
+		}
+	})
+}